@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/config"
+	"github.com/yassirdeveloper/migrater/internal/db"
+)
+
+var databaseOption = command.CommandOption{
+	Name:        "database",
+	Label:       "Database",
+	Description: "Registered database name",
+	Letter:      'd',
+	ValueType:   command.TypeString,
+}
+
+// getDatabase connects to the database selected with the database option,
+// or to the default one when the option is not given.
+func getDatabase(input command.CommandInput) (db.Database, errors.Error) {
+	databaseOpt, err := input.ParseOption(databaseOption)
+	if err != nil {
+		return nil, err
+	}
+	databaseName := ""
+	if databaseOpt != nil {
+		databaseName = databaseOpt.(string)
+	}
+	return connectDatabase(databaseName)
+}
+
+// connectDatabase connects to a registered database, or to the default one
+// when the name is empty.
+func connectDatabase(databaseName string) (db.Database, errors.Error) {
+	globalConfig, err := config.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	var databaseConfig config.DatabaseConfig
+	if databaseName != "" {
+		databaseConfig = globalConfig.GetDatabaseConfig(databaseName)
+		if databaseConfig == nil {
+			return nil, errors.New(fmt.Sprintf("Missing configuration for database: %s", databaseName))
+		}
+	} else {
+		databaseConfig = globalConfig.GetDefaultDatabaseConfig()
+		if databaseConfig == nil {
+			return nil, errors.New("No default database is configured")
+		}
+	}
+	return db.GetDatabase(databaseConfig)
+}
@@ -1,50 +1,43 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/yassirdeveloper/cli/command"
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/cli/operator"
-	"github.com/yassirdeveloper/migrater/internal/config"
 	"github.com/yassirdeveloper/migrater/internal/db"
 )
 
-var databaseOption = command.CommandOption{
-	Name:        "database",
-	Label:       "Database",
-	Description: "Registered database name",
-	Letter:      'd',
-	ValueType:   command.TypeString,
+// description is the structure of a database printed by describe, along
+// with the version of its server.
+type description struct {
+	db.SqlDatabase
+	Version string `json:"version,omitempty"`
 }
 
 func describeHandler(input command.CommandInput, operator operator.Operator) errors.Error {
-	databaseOpt, err := input.ParseOption(databaseOption)
+	format, err := getFormat(input)
 	if err != nil {
 		return operator.Write(err.Display())
 	}
-	globalConfig, err := config.GetGlobalConfig()
+	database, err := getDatabase(input)
 	if err != nil {
-		return operator.Write(err.Error())
+		return operator.Write(err.Display())
 	}
-	var databaseConfig config.DatabaseConfig
-	if databaseOpt != nil {
-		databaseName := databaseOpt.(string)
-		databaseConfig = globalConfig.GetDatabaseConfig(databaseName)
-		if databaseConfig == nil {
-			return operator.Write(fmt.Sprintf("Missing configuration for database: %s", databaseName))
-		}
-	} else {
-		databaseConfig = globalConfig.GetDefaultDatabaseConfig()
-		if databaseConfig == nil {
-			return operator.Write("No default database is configured")
-		}
+	if format == textFormat {
+		return operator.Write(database.Describe())
 	}
-	database, err := db.GetDatabase(databaseConfig)
+	output, err := formatValue(format, description{
+		Version: database.GetServerVersion(),
+		SqlDatabase: db.SqlDatabase{
+			DriverType: database.GetDriverType(),
+			Name:       database.GetName(),
+			Tables:     database.GetTables(),
+		},
+	})
 	if err != nil {
 		return operator.Write(err.Display())
 	}
-	return operator.Write(database.Describe())
+	return operator.Write(output)
 }
 
 func DescribeCommand() command.Command {
@@ -54,5 +47,6 @@ func DescribeCommand() command.Command {
 		describeHandler,
 	)
 	cmd.AddOption(databaseOption)
+	cmd.AddOption(formatOption)
 	return cmd
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// ExitError is returned by a command which failed, the process exits with
+// Code once the command is done. Err is the error to report, nil when the
+// command already wrote its output.
+type ExitError struct {
+	Code int
+	Err  errors.Error
+}
+
+// failure returns the ExitError reporting err with the exit code 1.
+func failure(err errors.Error) *ExitError {
+	return &ExitError{Code: 1, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Display()
+}
+
+func (e *ExitError) Display() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Display()
+}
+
+// exitCommand reports the ExitError returned by a command and records its
+// exit code, the other errors are returned as they are.
+type exitCommand struct {
+	command.Command
+	code *int
+}
+
+// WithExitCode wraps a command so that the exit code of its failure is
+// stored in code.
+func WithExitCode(c command.Command, code *int) command.Command {
+	return exitCommand{Command: c, code: code}
+}
+
+func (c exitCommand) Handle(input command.CommandInput, operator operator.Operator) errors.Error {
+	err := c.Command.Handle(input, operator)
+	exitErr, ok := err.(*ExitError)
+	if !ok {
+		return err
+	}
+	*c.code = exitErr.Code
+	if exitErr.Err == nil {
+		return nil
+	}
+	return operator.Write(exitErr.Display())
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	textFormat = "text"
+	jsonFormat = "json"
+	yamlFormat = "yaml"
+)
+
+var formatOption = command.CommandOption{
+	Name:        "format",
+	Label:       "Format",
+	Description: "Output format: text, json or yaml",
+	Letter:      'f',
+	ValueType:   command.TypeString,
+}
+
+// getFormat returns the output format selected with the format option, text
+// being the default.
+func getFormat(input command.CommandInput) (string, errors.Error) {
+	formatOpt, err := input.ParseOption(formatOption)
+	if err != nil {
+		return "", err
+	}
+	if formatOpt == nil {
+		return textFormat, nil
+	}
+	format := strings.ToLower(formatOpt.(string))
+	switch format {
+	case textFormat, jsonFormat, yamlFormat:
+		return format, nil
+	default:
+		return "", errors.New(fmt.Sprintf("Invalid format: %s, expected text, json or yaml", formatOpt))
+	}
+}
+
+// formatValue encodes a value as json or yaml. The yaml output is converted
+// from the json one, so that both follow the json encoding of the value.
+func formatValue(format string, value any) (string, errors.Error) {
+	data, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	if format == jsonFormat {
+		return string(data), nil
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	data, err = yaml.Marshal(generic)
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
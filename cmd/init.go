@@ -1 +1,113 @@
-package cmd
\ No newline at end of file
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+	"github.com/yassirdeveloper/migrater/internal/config"
+	"github.com/yassirdeveloper/migrater/internal/db"
+	"github.com/yassirdeveloper/migrater/internal/migrater"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+const schemaFilePath = "database.json"
+
+const starterConfig = `database "main" {
+  driver  = "sqlite"
+  dsn     = "file:main.db?cache=shared&mode=rwc"
+  default = true
+}
+`
+
+const starterSchema = `{
+    "name": "main",
+    "driver": "sqlite",
+    "tables": [
+        {
+            "name": "users",
+            "columns": [
+                {"name": "id", "type": "INTEGER", "constraints": ["primary_key"]},
+                {"name": "name", "type": "TEXT", "constraints": ["not_null"]}
+            ]
+        }
+    ]
+}
+`
+
+var fromOption = command.CommandOption{
+	Name:        "from",
+	Label:       "From",
+	Description: "Registered database name to write the schema of",
+	Letter:      'f',
+	ValueType:   command.TypeString,
+}
+
+// writeNewFile writes a file unless it already exists, in which case it is
+// left untouched.
+func writeNewFile(operator operator.Operator, path string, content []byte) errors.Error {
+	if _, err := os.Stat(path); err == nil {
+		return operator.Write(fmt.Sprintf("Skipped %s: the file already exists.", path))
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return errors.New(fmt.Sprintf("Cannot write file: %s", path))
+	}
+	return operator.Write(fmt.Sprintf("Created %s", path))
+}
+
+// currentSchema returns the structure of a registered database as a
+// schema file, leaving out the history of migrations.
+func currentSchema(databaseName string) ([]byte, errors.Error) {
+	database, err := connectDatabase(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	tables := slices.DeleteFunc(slices.Clone(database.GetTables()), func(table schema.Table) bool {
+		return table.Name == migrater.HistoryTableName
+	})
+	return db.ToJSON(&db.SqlDatabase{
+		DriverType: database.GetDriverType(),
+		Name:       database.GetName(),
+		Tables:     tables,
+	})
+}
+
+func initHandler(input command.CommandInput, operator operator.Operator) errors.Error {
+	fromOpt, err := input.ParseOption(fromOption)
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	if fromOpt == nil {
+		err = writeNewFile(operator, config.GetGlobalConfigFilePath(), []byte(starterConfig))
+		if err != nil {
+			return operator.Write(err.Display())
+		}
+		err = writeNewFile(operator, schemaFilePath, []byte(starterSchema))
+		if err != nil {
+			return operator.Write(err.Display())
+		}
+		return nil
+	}
+	content, err := currentSchema(fromOpt.(string))
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	err = writeNewFile(operator, schemaFilePath, content)
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	return nil
+}
+
+func InitCommand() command.Command {
+	cmd := command.NewCommand(
+		"init",
+		"Creates a starter config.hcl and database.json, or writes the structure of a configured database to database.json.",
+		initHandler,
+	)
+	cmd.AddOption(fromOption)
+	return cmd
+}
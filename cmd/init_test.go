@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/config"
+	"github.com/yassirdeveloper/migrater/internal/db"
+)
+
+func TestInitCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	output := runCommand(t, InitCommand())
+	if output != "Created config.hcl\nCreated database.json" {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	globalConfig, err := config.GetGlobalConfig()
+	if err != nil {
+		t.Fatalf("Invalid starter config: %s", err)
+	}
+	if globalConfig.GetDefaultDatabaseConfig() == nil {
+		t.Errorf("Expected a default database in the starter config")
+	}
+	desired, err := db.LoadFromJSON("database.json")
+	if err != nil {
+		t.Fatalf("Invalid starter schema: %s", err)
+	}
+	if errs := desired.Validate(); len(errs) > 0 {
+		t.Errorf("Invalid starter schema: %v", errs)
+	}
+	output = runCommand(t, InitCommand())
+	if !strings.Contains(output, "Skipped config.hcl") || !strings.Contains(output, "Skipped database.json") {
+		t.Errorf("Expected existing files to be skipped, got:\n%s", output)
+	}
+}
+
+func TestInitCommandFromDatabase(t *testing.T) {
+	setupSqliteProject(t)
+	runCommand(t, MigrateCommand("test"), "database.json")
+	if err := os.Remove("database.json"); err != nil {
+		t.Fatal(err)
+	}
+	output := runCommand(t, InitCommand(), "--from", "test")
+	if output != "Created database.json" {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	pulled, err := db.LoadFromJSON("database.json")
+	if err != nil {
+		t.Fatalf("Invalid schema: %s", err)
+	}
+	tables := pulled.GetTables()
+	if len(tables) != 1 || tables[0].Name != "users" || len(tables[0].Columns) != 2 {
+		t.Errorf("Unexpected tables: %v", tables)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+	"github.com/yassirdeveloper/migrater/internal/db"
+	"github.com/yassirdeveloper/migrater/internal/migrater"
+)
+
+var allowDestructiveOption = command.CommandOption{
+	Name:        "allow-destructive",
+	Label:       "Allow destructive",
+	Description: "Apply changes that can lose data, such as dropping tables or columns",
+	Letter:      'y',
+	ValueType:   command.TypeBool,
+}
+
+// loadSchema loads the desired schema from the json file given as argument
+// and makes sure it is valid.
+func loadSchema(input command.CommandInput) (db.Database, errors.Error) {
+	filePathArg, err := input.ParseArgument(jsonFilePathArgument)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := db.LoadFromJSON(filePathArg.(string))
+	if err != nil {
+		return nil, err
+	}
+	errs := db.WithoutWarnings(desired.Validate())
+	if len(errs) > 0 {
+		message := "Invalid database structure:\n"
+		for _, err := range errs {
+			message += fmt.Sprintf("- %s\n", err.Display())
+		}
+		return nil, errors.New(message)
+	}
+	return desired, nil
+}
+
+// migrateHandler fails with an ExitError when the migration fails or is
+// refused, so that scripts can tell it was not applied.
+func migrateHandler(version string) func(command.CommandInput, operator.Operator) errors.Error {
+	return func(input command.CommandInput, operator operator.Operator) errors.Error {
+		desired, err := loadSchema(input)
+		if err != nil {
+			return failure(err)
+		}
+		database, err := getDatabase(input)
+		if err != nil {
+			return failure(err)
+		}
+		allowDestructiveOpt, err := input.ParseOption(allowDestructiveOption)
+		if err != nil {
+			return failure(err)
+		}
+		allowDestructive := allowDestructiveOpt != nil && allowDestructiveOpt.(bool)
+		err = migrater.NewMigrater(desired, version, operator, allowDestructive).Apply(database)
+		if err != nil {
+			return failure(err)
+		}
+		return operator.Write("Migration completed!")
+	}
+}
+
+func MigrateCommand(version string) command.Command {
+	cmd := command.NewCommand(
+		"migrate",
+		"Migrates the database to the structure of the database schema in the json file.",
+		migrateHandler(version),
+	)
+	cmd.AddArgument(jsonFilePathArgument)
+	cmd.AddOption(databaseOption)
+	cmd.AddOption(allowDestructiveOption)
+	return cmd
+}
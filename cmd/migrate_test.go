@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/config"
+	"github.com/yassirdeveloper/migrater/internal/db"
+)
+
+const testSchema = `{
+    "name": "test",
+    "driver": "sqlite",
+    "tables": [
+        {
+            "name": "users",
+            "columns": [
+                {"name": "id", "type": "INTEGER"},
+                {"name": "name", "type": "TEXT"}
+            ]
+        }
+    ]
+}`
+
+// setupSqliteProject creates a config.hcl pointing at a fresh sqlite
+// database and a schema file in a temporary working directory.
+func setupSqliteProject(t *testing.T) string {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	configContent := fmt.Sprintf(`database "test" {
+  driver  = "sqlite"
+  dsn     = "file:%s?cache=shared&mode=rwc"
+  default = true
+}
+`, filepath.Join(dir, "test.db"))
+	if err := os.WriteFile("config.hcl", []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("database.json", []byte(testSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestMigrateCommand(t *testing.T) {
+	setupSqliteProject(t)
+	c := MigrateCommand("test")
+	if c.String() != "migrate" {
+		t.Errorf("Expected command name 'migrate', got '%s'", c.String())
+	}
+	input, err := c.Parse([]string{"database.json"})
+	if err != nil {
+		t.Fatalf("Failed to parse command: %s", err)
+	}
+	err = c.Handle(input, &MockOperator{})
+	if err != nil {
+		t.Fatalf("Failed to handle command: %s", err)
+	}
+	globalConfig, err := config.GetGlobalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load config: %s", err)
+	}
+	database, err := db.GetDatabase(globalConfig.GetDefaultDatabaseConfig())
+	if err != nil {
+		t.Fatalf("Failed to connect: %s", err)
+	}
+	found := false
+	for _, table := range database.GetTables() {
+		if table.Name == "users" {
+			found = len(table.Columns) == 2
+		}
+	}
+	if !found {
+		t.Errorf("Unexpected tables after migration: %v", database.GetTables())
+	}
+}
+
+func TestMigrateCommandFailures(t *testing.T) {
+	setupSqliteProject(t)
+	runCommand(t, MigrateCommand("test"), "database.json")
+	dropped := `{"name": "test", "driver": "sqlite", "tables": [{"name": "posts", "columns": [{"name": "id", "type": "INTEGER"}]}]}`
+	if err := os.WriteFile("dropped.json", []byte(dropped), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		output string
+	}{
+		{name: "refused destructive changes", args: []string{"dropped.json"}, output: `"- table users"`},
+		{name: "missing schema", args: []string{"missing.json"}, output: "missing.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := 0
+			c := WithExitCode(MigrateCommand("test"), &code)
+			input, err := c.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Failed to parse command: %s", err)
+			}
+			operator := &RecordingOperator{}
+			if err := c.Handle(input, operator); err != nil {
+				t.Fatalf("Failed to handle command: %s", err)
+			}
+			if code != 1 {
+				t.Errorf("Expected exit code 1, got %d", code)
+			}
+			if !strings.Contains(operator.Output(), tt.output) {
+				t.Errorf("Expected output containing %s, got:\n%s", tt.output, operator.Output())
+			}
+		})
+	}
+}
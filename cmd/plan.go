@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+	"github.com/yassirdeveloper/migrater/internal/migrater"
+)
+
+// planHandler fails with an ExitError when the plan cannot be made.
+func planHandler(version string) func(command.CommandInput, operator.Operator) errors.Error {
+	return func(input command.CommandInput, operator operator.Operator) errors.Error {
+		desired, err := loadSchema(input)
+		if err != nil {
+			return failure(err)
+		}
+		database, err := getDatabase(input)
+		if err != nil {
+			return failure(err)
+		}
+		plan, err := migrater.NewMigrater(desired, version, nil, false).Plan(database)
+		if err != nil {
+			return failure(err)
+		}
+		return operator.Write(plan)
+	}
+}
+
+func PlanCommand(version string) command.Command {
+	cmd := command.NewCommand(
+		"plan",
+		"Prints the SQL statements a migration would run without executing them.",
+		planHandler(version),
+	)
+	cmd.AddArgument(jsonFilePathArgument)
+	cmd.AddOption(databaseOption)
+	return cmd
+}
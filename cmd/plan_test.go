@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yassirdeveloper/cli/command"
+)
+
+// runFailingCommand runs a command wrapped with WithExitCode and returns
+// its output and exit code.
+func runFailingCommand(t *testing.T, c command.Command, args ...string) (string, int) {
+	code := 0
+	c = WithExitCode(c, &code)
+	input, err := c.Parse(args)
+	if err != nil {
+		t.Fatalf("Failed to parse command: %s", err)
+	}
+	operator := &RecordingOperator{}
+	if err := c.Handle(input, operator); err != nil {
+		t.Fatalf("Failed to handle command: %s", err)
+	}
+	return operator.Output(), code
+}
+
+func TestPlanCommandFailure(t *testing.T) {
+	setupSqliteProject(t)
+	output, code := runFailingCommand(t, PlanCommand("test"), "missing.json")
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(output, "missing.json") {
+		t.Errorf("Expected output containing missing.json, got:\n%s", output)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+var outputOption = command.CommandOption{
+	Name:        "output",
+	Label:       "Output",
+	Description: "File to write the schema to instead of printing it",
+	Letter:      'o',
+	ValueType:   command.TypeString,
+}
+
+func pullHandler(input command.CommandInput, operator operator.Operator) errors.Error {
+	databaseOpt, err := input.ParseOption(databaseOption)
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	outputOpt, err := input.ParseOption(outputOption)
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	databaseName := ""
+	if databaseOpt != nil {
+		databaseName = databaseOpt.(string)
+	}
+	content, err := currentSchema(databaseName)
+	if err != nil {
+		return operator.Write(err.Display())
+	}
+	if outputOpt == nil {
+		return operator.Write(strings.TrimSuffix(string(content), "\n"))
+	}
+	path := outputOpt.(string)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return operator.Write(errors.New(fmt.Sprintf("Cannot write file: %s", path)).Display())
+	}
+	return operator.Write(fmt.Sprintf("Wrote %s", path))
+}
+
+func PullCommand() command.Command {
+	cmd := command.NewCommand(
+		"pull",
+		"Prints the structure of the database in the format of database.json, or writes it to a file.",
+		pullHandler,
+	)
+	cmd.AddOption(databaseOption)
+	cmd.AddOption(outputOption)
+	return cmd
+}
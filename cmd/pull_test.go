@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+const pulledTestSchema = `{
+    "driver": "sqlite",
+    "name": "test",
+    "tables": [
+        {
+            "name": "users",
+            "columns": [
+                {
+                    "name": "id",
+                    "type": "INTEGER"
+                },
+                {
+                    "name": "name",
+                    "type": "TEXT"
+                }
+            ]
+        }
+    ]
+}`
+
+func TestPullCommand(t *testing.T) {
+	setupSqliteProject(t)
+	runCommand(t, MigrateCommand("test"), "database.json")
+	output := runCommand(t, PullCommand(), "-d", "test")
+	if output != pulledTestSchema {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	output = runCommand(t, PullCommand(), "-o", "pulled.json")
+	if output != "Wrote pulled.json" {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	content, err := os.ReadFile("pulled.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != pulledTestSchema+"\n" {
+		t.Errorf("Unexpected file content:\n%s", content)
+	}
+}
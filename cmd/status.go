@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+	"github.com/yassirdeveloper/migrater/internal/migrater"
+)
+
+// statusHandler fails with an ExitError when the status cannot be read or
+// reports that the database drifted, so that scripts can check it.
+func statusHandler(version string) func(command.CommandInput, operator.Operator) errors.Error {
+	return func(input command.CommandInput, operator operator.Operator) errors.Error {
+		database, err := getDatabase(input)
+		if err != nil {
+			return failure(err)
+		}
+		status, drifted, err := migrater.NewMigrater(nil, version, nil, false).Status(database)
+		if err != nil {
+			return failure(err)
+		}
+		if err := operator.Write(status); err != nil {
+			return err
+		}
+		if drifted {
+			return &ExitError{Code: 1}
+		}
+		return nil
+	}
+}
+
+func StatusCommand(version string) command.Command {
+	cmd := command.NewCommand(
+		"status",
+		"Prints when the database was last migrated and whether it matches the applied schema.",
+		statusHandler(version),
+	)
+	cmd.AddOption(databaseOption)
+	return cmd
+}
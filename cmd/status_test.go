@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/config"
+	"github.com/yassirdeveloper/migrater/internal/db"
+)
+
+// RecordingOperator keeps the written messages for inspection.
+type RecordingOperator struct {
+	messages []string
+}
+
+func (r *RecordingOperator) Write(message string) errors.Error {
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *RecordingOperator) Read() (string, errors.Error) {
+	return "", nil
+}
+
+func (r *RecordingOperator) Output() string {
+	return strings.Join(r.messages, "\n")
+}
+
+func runCommand(t *testing.T, c command.Command, args ...string) string {
+	input, err := c.Parse(args)
+	if err != nil {
+		t.Fatalf("Failed to parse command: %s", err)
+	}
+	operator := &RecordingOperator{}
+	err = c.Handle(input, operator)
+	if err != nil {
+		t.Fatalf("Failed to handle command: %s", err)
+	}
+	return operator.Output()
+}
+
+func TestStatusCommand(t *testing.T) {
+	setupSqliteProject(t)
+	output := runCommand(t, StatusCommand("test"))
+	if !strings.Contains(output, "Never migrated.") {
+		t.Errorf("Expected a never migrated status, got:\n%s", output)
+	}
+	runCommand(t, MigrateCommand("test"), "database.json")
+	output = runCommand(t, StatusCommand("test"))
+	if !strings.Contains(output, "with migrater test") {
+		t.Errorf("Expected the last migration in the status, got:\n%s", output)
+	}
+	if !strings.Contains(output, "The database matches the last applied schema.") {
+		t.Errorf("Expected the database to match the applied schema, got:\n%s", output)
+	}
+	output = runCommand(t, MigrateCommand("test"), "database.json")
+	if !strings.Contains(output, "Nothing to migrate.") {
+		t.Errorf("Expected nothing to migrate on the second run, got:\n%s", output)
+	}
+}
+
+func TestStatusCommandDrift(t *testing.T) {
+	setupSqliteProject(t)
+	runCommand(t, MigrateCommand("test"), "database.json")
+	if output, code := runFailingCommand(t, StatusCommand("test")); code != 0 {
+		t.Fatalf("Expected exit code 0 before the drift, got %d:\n%s", code, output)
+	}
+	globalConfig, err := config.GetGlobalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load config: %s", err)
+	}
+	database, err := db.GetDatabase(globalConfig.GetDefaultDatabaseConfig())
+	if err != nil {
+		t.Fatalf("Failed to connect: %s", err)
+	}
+	if err := database.Execute(`ALTER TABLE "users" ADD COLUMN "email" TEXT`); err != nil {
+		t.Fatalf("Failed to alter the table: %s", err)
+	}
+	output, code := runFailingCommand(t, StatusCommand("test"))
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(output, "The database has drifted from the last applied schema") {
+		t.Errorf("Expected the drift in the status, got:\n%s", output)
+	}
+}
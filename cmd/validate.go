@@ -16,40 +16,81 @@ var jsonFilePathArgument = command.CommandArgument{
 	ValueType:   command.TypeString,
 }
 
+// validateHandler writes the validation errors of a schema file, or the
+// error loading it, in the requested format. It fails with an ExitError
+// when the schema is invalid, warnings alone leave it valid.
 func validateHandler(input command.CommandInput, operator operator.Operator) errors.Error {
+	format, err := getFormat(input)
+	if err != nil {
+		return failure(err)
+	}
 	filePathArg, err := input.ParseArgument(jsonFilePathArgument)
 	if err != nil {
-		err = operator.Write(err.Display())
-		if err != nil {
-			return errors.NewUnexpectedError(err)
-		}
+		return failure(err)
 	}
 	filePath := filePathArg.(string)
-	db, err := db.LoadFromJSON(filePath)
+	var errs []db.ValidationError
+	database, err := db.LoadFromJSON(filePath)
 	if err != nil {
-		err = operator.Write(err.Display())
+		errs = []db.ValidationError{{Code: db.InvalidSchemaFileCode, Message: err.Display()}}
+	} else {
+		errs = database.Validate()
+	}
+	if format != textFormat {
+		output, err := formatValue(format, errs)
+		if err != nil {
+			return failure(err)
+		}
+		err = operator.Write(output)
 		if err != nil {
 			return errors.NewUnexpectedError(err)
 		}
-		return nil
-	}
-	errs := db.Validate()
-	if len(errs) > 0 {
+	} else if database == nil {
+		err = operator.Write(errs[0].Message)
+		if err != nil {
+			return errors.NewUnexpectedError(err)
+		}
+	} else if len(db.WithoutWarnings(errs)) > 0 {
 		err = operator.Write("Invalid database structure:\n")
 		if err != nil {
 			return errors.NewUnexpectedError(err)
 		}
-		for _, err := range errs {
-			err = operator.Write(fmt.Sprintf("- %s\n", err.Display()))
-			if err != nil {
-				return errors.NewUnexpectedError(err)
-			}
+		err = writeValidationErrors(operator, errs)
+		if err != nil {
+			return err
+		}
+	} else {
+		header := "Valid database structure!"
+		if len(errs) > 0 {
+			header += "\n"
+		}
+		err = operator.Write(header)
+		if err != nil {
+			return errors.NewUnexpectedError(err)
+		}
+		err = writeValidationErrors(operator, errs)
+		if err != nil {
+			return err
 		}
-		return nil
 	}
-	err = operator.Write("Valid database structure!")
-	if err != nil {
-		return errors.NewUnexpectedError(err)
+	if len(db.WithoutWarnings(errs)) > 0 {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// writeValidationErrors writes one validation error per line, warnings
+// being marked as such.
+func writeValidationErrors(operator operator.Operator, errs []db.ValidationError) errors.Error {
+	for _, err := range errs {
+		message := err.Display()
+		if err.Warning {
+			message = "warning: " + message
+		}
+		writeErr := operator.Write(fmt.Sprintf("- %s\n", message))
+		if writeErr != nil {
+			return errors.NewUnexpectedError(writeErr)
+		}
 	}
 	return nil
 }
@@ -61,5 +102,6 @@ func ValidateCommand() command.Command {
 		validateHandler,
 	)
 	cmd.AddArgument(jsonFilePathArgument)
+	cmd.AddOption(formatOption)
 	return cmd
 }
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/db"
+)
+
+const invalidTestSchema = `{
+    "name": "test",
+    "driver": "sqlite",
+    "tables": [
+        {
+            "name": "users",
+            "columns": [
+                {"name": "id", "type": "UUID"}
+            ]
+        }
+    ]
+}`
+
+const ambiguousTestSchema = `{
+    "name": "test",
+    "driver": "postgres",
+    "tables": [
+        {
+            "name": "users",
+            "columns": [
+                {"name": "data", "type": "json"}
+            ]
+        }
+    ]
+}`
+
+func TestValidateCommandFormats(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("valid.json", []byte(testSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("invalid.json", []byte(invalidTestSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("ambiguous.json", []byte(ambiguousTestSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		output string
+		code   int
+	}{
+		{
+			name:   "valid text",
+			args:   []string{"valid.json"},
+			output: "Valid database structure!",
+			code:   0,
+		},
+		{
+			name:   "valid yaml",
+			args:   []string{"--format", "yaml", "valid.json"},
+			output: "[]",
+			code:   0,
+		},
+		{
+			name:   "invalid text",
+			args:   []string{"invalid.json"},
+			output: "Invalid database structure:\n\n- invalid data type: UUID for column id\n",
+			code:   1,
+		},
+		{
+			name:   "warning text",
+			args:   []string{"ambiguous.json"},
+			output: "Valid database structure!\n\n- warning: type json of column data is the logical type mapped to JSONB, write JSON for the driver type\n",
+			code:   0,
+		},
+		{
+			name:   "missing file text",
+			args:   []string{"missing.json"},
+			output: "Cannot open file! missing.json",
+			code:   1,
+		},
+		{
+			name:   "missing file yaml",
+			args:   []string{"--format", "yaml", "missing.json"},
+			output: "- code: invalid_schema_file\n  message: Cannot open file! missing.json",
+			code:   1,
+		},
+		{
+			name:   "invalid yaml",
+			args:   []string{"--format", "yaml", "invalid.json"},
+			output: "- code: invalid_type\n  column: id\n  message: 'invalid data type: UUID for column id'\n  table: users",
+			code:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := 0
+			output := runCommand(t, WithExitCode(ValidateCommand(), &code), tt.args...)
+			if output != tt.output {
+				t.Errorf("Expected output:\n%s\ngot:\n%s", tt.output, output)
+			}
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestValidateCommandJSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("invalid.json", []byte(invalidTestSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code := 0
+	output := runCommand(t, WithExitCode(ValidateCommand(), &code), "--format", "json", "invalid.json")
+	var errs []db.ValidationError
+	if err := json.Unmarshal([]byte(output), &errs); err != nil {
+		t.Fatalf("Invalid json output: %s\n%s", err, output)
+	}
+	expected := []db.ValidationError{
+		{Code: db.InvalidTypeCode, Table: "users", Column: "id", Message: "invalid data type: UUID for column id"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected %v, got %v", expected, errs)
+	}
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
+
+func TestDescribeCommandJSON(t *testing.T) {
+	setupSqliteProject(t)
+	runCommand(t, MigrateCommand("test"), "database.json")
+	output := runCommand(t, DescribeCommand(), "--format", "json")
+	var described db.SqlDatabase
+	if err := json.Unmarshal([]byte(output), &described); err != nil {
+		t.Fatalf("Invalid json output: %s\n%s", err, output)
+	}
+	if described.Name != "test" || described.DriverType != "sqlite" {
+		t.Errorf("Unexpected database: %s %s", described.Name, described.DriverType)
+	}
+	found := false
+	for _, table := range described.Tables {
+		if table.Name == "users" {
+			found = len(table.Columns) == 2
+		}
+	}
+	if !found {
+		t.Errorf("Expected the users table, got:\n%s", output)
+	}
+}
@@ -15,6 +15,11 @@ import (
 
 var globalConfigFilePath = "config.hcl"
 
+// GetGlobalConfigFilePath returns the path of the configuration file.
+func GetGlobalConfigFilePath() string {
+	return globalConfigFilePath
+}
+
 type GlobalConfig interface {
 	GetDefaultDatabaseConfig() DatabaseConfig
 	GetDatabaseConfig(string) DatabaseConfig
@@ -90,6 +95,9 @@ func (c *globalConfig) Validate() errors.Error {
 		}
 		driverName := d.Driver
 		if driver := drivers.GetDriver(driverName); driver == nil {
+			if driverName == drivers.DuckdbDriverType {
+				return errors.New("The duckdb driver is not part of this build, build migrater with -tags duckdb to use it")
+			}
 			return errors.New(fmt.Sprintf("Invalid driver name: %s\nSupported drivers: %s", d.Driver, drivers.SupportedDrivers[:]))
 		}
 	}
@@ -115,12 +123,16 @@ type databaseConfig struct {
 func (d *databaseConfig) GetDSN() (*utils.DSN, errors.Error) {
 	var format utils.DSNFormat
 	switch d.Driver {
-	case drivers.MysqlDriverType:
+	case drivers.MysqlDriverType, drivers.MariadbDriverType:
 		format = utils.DSNFormatMySQL
-	case drivers.PostgresDriverType:
+	case drivers.PostgresDriverType, drivers.CockroachdbDriverType:
 		format = utils.DSNFormatPostgres
 	case drivers.SqliteDriverType:
 		format = utils.DSNFormatSQLite
+	case drivers.SqlserverDriverType:
+		format = utils.DSNFormatSQLServer
+	case drivers.DuckdbDriverType:
+		format = utils.DSNFormatDuckDB
 	default:
 		return nil, errors.New(fmt.Sprintf("Unsupported driver: %s", d.Driver))
 	}
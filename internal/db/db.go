@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/migrater/internal/config"
@@ -18,8 +20,16 @@ type Database interface {
 	DSN() utils.DSN
 	Execute(string) errors.Error
 	Query(string) (drivers.Result, errors.Error)
-	Validate() []errors.Error
+	Begin() errors.Error
+	Commit() errors.Error
+	Rollback() errors.Error
+	Validate() []ValidationError
 	Describe() string
+	GetName() string
+	GetDriverType() drivers.DriverType
+	GetServerVersion() string
+	GetTables() []schema.Table
+	GetAllowedDestructiveChanges() []string
 }
 
 func GetDatabase(config config.DatabaseConfig) (Database, errors.Error) {
@@ -56,12 +66,61 @@ func LoadFromJSON(filePath string) (Database, errors.Error) {
 	return &db, nil
 }
 
+// ToJSON encodes the structure of a database in the format read by
+// LoadFromJSON. Tables, constraints and indexes are sorted by name so that
+// the same structure always gives the same output, columns keep their
+// position in the table.
+func ToJSON(database Database) ([]byte, errors.Error) {
+	structure := SqlDatabase{
+		DriverType: database.GetDriverType(),
+		Name:       database.GetName(),
+		Tables:     sortedTables(database.GetTables()),
+	}
+	data, err := json.MarshalIndent(structure, "", "    ")
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return append(data, '\n'), nil
+}
+
+// sortedTables returns a copy of the tables sorted by name, along with their
+// constraints and indexes.
+func sortedTables(tables []schema.Table) []schema.Table {
+	sorted := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		columns := make([]schema.Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			column.Constraints = slices.SortedStableFunc(slices.Values(column.Constraints), func(a, b schema.Constraint) int {
+				return strings.Compare(a.Name(), b.Name())
+			})
+			columns = append(columns, column)
+		}
+		table.Columns = columns
+		table.Constraints = slices.SortedStableFunc(slices.Values(table.Constraints), func(a, b schema.TableConstraint) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		table.Indexes = slices.SortedStableFunc(slices.Values(table.Indexes), func(a, b schema.Index) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		sorted = append(sorted, table)
+	}
+	slices.SortStableFunc(sorted, func(a, b schema.Table) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+// SqlDatabase is a database structure, either read from a schema file or
+// introspected from a live database. AllowDestructive lists destructive
+// changes, as printed by the planner, that may be applied without the
+// --allow-destructive flag.
 type SqlDatabase struct {
-	DriverType drivers.DriverType `json:"driver"`
-	Name       string             `json:"name"`
-	Tables     []schema.Table     `json:"tables"`
-	driver     drivers.Driver
-	dsn        utils.DSN
+	DriverType       drivers.DriverType `json:"driver"`
+	Name             string             `json:"name"`
+	Tables           []schema.Table     `json:"tables"`
+	AllowDestructive []string           `json:"allow_destructive,omitempty"`
+	driver           drivers.Driver
+	dsn              utils.DSN
 }
 
 func (d *SqlDatabase) Init() errors.Error {
@@ -93,6 +152,31 @@ func (d *SqlDatabase) DSN() utils.DSN {
 	return d.dsn
 }
 
+func (d *SqlDatabase) GetName() string {
+	return d.Name
+}
+
+func (d *SqlDatabase) GetDriverType() drivers.DriverType {
+	return d.DriverType
+}
+
+// GetServerVersion returns the version of the connected server, or an empty
+// string for a structure read from a schema file.
+func (d *SqlDatabase) GetServerVersion() string {
+	if d.driver == nil {
+		return ""
+	}
+	return d.driver.Version().String()
+}
+
+func (d *SqlDatabase) GetTables() []schema.Table {
+	return d.Tables
+}
+
+func (d *SqlDatabase) GetAllowedDestructiveChanges() []string {
+	return d.AllowDestructive
+}
+
 func (d *SqlDatabase) Execute(query string) errors.Error {
 	return d.driver.Execute(query)
 }
@@ -101,6 +185,18 @@ func (d *SqlDatabase) Query(query string) (drivers.Result, errors.Error) {
 	return d.driver.Query(query)
 }
 
+func (d *SqlDatabase) Begin() errors.Error {
+	return d.driver.Begin()
+}
+
+func (d *SqlDatabase) Commit() errors.Error {
+	return d.driver.Commit()
+}
+
+func (d *SqlDatabase) Rollback() errors.Error {
+	return d.driver.Rollback()
+}
+
 func (d *SqlDatabase) Describe() string {
 	tablesSummary := ""
 	for _, table := range d.Tables {
@@ -111,61 +207,245 @@ func (d *SqlDatabase) Describe() string {
 				tablesSummary += fmt.Sprintf("    Constraints: %s\n", column.Constraints)
 			}
 		}
+		for _, constraint := range table.Constraints {
+			tablesSummary += fmt.Sprintf("  Constraint: %s\n", constraint)
+		}
+		for _, index := range table.Indexes {
+			tablesSummary += fmt.Sprintf("  Index: %s\n", index)
+		}
 	}
 	if tablesSummary == "" {
 		tablesSummary = "No tables found."
 	}
-	return fmt.Sprintf("Database: %s\nDriver: %s\nTables:\n%s", d.Name, d.DriverType, tablesSummary)
+	header := fmt.Sprintf("Database: %s\nDriver: %s\n", d.Name, d.DriverType)
+	if version := d.GetServerVersion(); version != "" {
+		header += fmt.Sprintf("Version: %s\n", version)
+	}
+	return fmt.Sprintf("%sTables:\n%s", header, tablesSummary)
 }
 
-func (s *SqlDatabase) Validate() []errors.Error {
-	errs := make([]errors.Error, 0)
+func (s *SqlDatabase) Validate() []ValidationError {
+	errs := make([]ValidationError, 0)
 	driver := drivers.GetDriver(s.DriverType)
 	if driver == nil {
 		if s.DriverType == "" {
-			errs = append(errs, errors.New("missing driver"))
+			errs = append(errs, validationError(MissingDriverCode, "", "", "missing driver"))
 		} else {
-			errs = append(errs, errors.New(fmt.Sprintf("invalid driver: %s", s.DriverType)))
+			errs = append(errs, validationError(InvalidDriverCode, "", "", fmt.Sprintf("invalid driver: %s", s.DriverType)))
 		}
 	}
 	if s.Name == "" {
-		errs = append(errs, errors.New("database name cannot be empty"))
+		errs = append(errs, validationError(MissingNameCode, "", "", "database name cannot be empty"))
 	}
 	if len(s.Tables) == 0 {
-		errs = append(errs, errors.New("schema must have at least one table"))
+		errs = append(errs, validationError(MissingTablesCode, "", "", "schema must have at least one table"))
 	}
 	tableNames := make(map[string]bool)
+	indexNames := make(map[string]bool)
 	for _, table := range s.Tables {
 		if tableNames[table.Name] {
-			errs = append(errs, errors.New(fmt.Sprintf("duplicate table name: %s", table.Name)))
+			errs = append(errs, validationError(DuplicateNameCode, table.Name, "", fmt.Sprintf("duplicate table name: %s", table.Name)))
 		}
 		tableNames[table.Name] = true
 		err := utils.ValidateSQLName(table.Name)
 		if err != nil {
-			errs = append(errs, errors.New(fmt.Sprintf("invalid table name: %s (%s)", table.Name, err.Display())))
+			errs = append(errs, validationError(InvalidNameCode, table.Name, "", fmt.Sprintf("invalid table name: %s (%s)", table.Name, err.Display())))
 		}
+		errs = append(errs, validateRenamedFrom(table.Name, "", table.RenamedFrom, table.Name)...)
 		columnNames := make(map[string]bool)
 		for _, column := range table.Columns {
 			if columnNames[column.Name] {
-				errs = append(errs, errors.New(fmt.Sprintf("duplicate column name: %s in table %s", column.Name, table.Name)))
+				errs = append(errs, validationError(DuplicateNameCode, table.Name, column.Name, fmt.Sprintf("duplicate column name: %s in table %s", column.Name, table.Name)))
 			}
 			columnNames[column.Name] = true
 			err = utils.ValidateSQLName(column.Name)
 			if err != nil {
-				errs = append(errs, errors.New(fmt.Sprintf("invalid column name: %s (%s)", column.Name, err.Display())))
+				errs = append(errs, validationError(InvalidNameCode, table.Name, column.Name, fmt.Sprintf("invalid column name: %s (%s)", column.Name, err.Display())))
 			}
+			errs = append(errs, validateRenamedFrom(table.Name, column.Name, column.RenamedFrom, column.Name)...)
 			if column.Type == "" {
-				errs = append(errs, errors.New(fmt.Sprintf("type of column %s cannot be empty", column.Name)))
+				errs = append(errs, validationError(MissingTypeCode, table.Name, column.Name, fmt.Sprintf("type of column %s cannot be empty", column.Name)))
 			}
-			if !drivers.HasType(driver, column.Type) {
-				errs = append(errs, errors.New(fmt.Sprintf("invalid data type: %s for column %s", column.Type, column.Name)))
+			if driver != nil && !drivers.HasType(driver, column.Type) {
+				errs = append(errs, validationError(InvalidTypeCode, table.Name, column.Name, fmt.Sprintf("invalid data type: %s for column %s", column.Type, column.Name)))
+			} else if driver != nil {
+				errs = append(errs, validateLogicalType(driver, table.Name, column)...)
 			}
-			// Add constraints validation here
-			// for _, constraint := range column.Constraints {
-			// 	if !isValidConstraint(constraint) {
-			// 		errs = append(errs, errors.New(fmt.Sprintf("invalid constraint: %s for column %s", constraint, column.Name)))
-			// 	}
-			// }
+		}
+		errs = append(errs, validateTableConstraints(table, columnNames)...)
+		if driver != nil && driver.IndexNamesPerTable() {
+			indexNames = make(map[string]bool)
+		}
+		for _, index := range table.Indexes {
+			errs = append(errs, validateIndex(driver, table, index, columnNames, indexNames)...)
+		}
+	}
+	return append(errs, validateReferences(s.Tables)...)
+}
+
+// validateLogicalType warns when the type of a column is a logical type
+// named like a type of the driver it does not map to, e.g. json which maps
+// to JSONB on postgres, since it may have been meant as the driver type.
+func validateLogicalType(driver drivers.Driver, table string, column schema.Column) []ValidationError {
+	logical, _, ok := column.Type.Logical()
+	if !ok {
+		return nil
+	}
+	native := schema.DataType(strings.ToUpper(string(logical)))
+	if !drivers.HasType(driver, native) {
+		return nil
+	}
+	resolved, err := driver.ResolveType(schema.DataType(logical))
+	if err != nil {
+		return nil
+	}
+	normalized, _ := driver.NormalizeType(native)
+	if resolved.Parse().Base == normalized.Base {
+		return nil
+	}
+	return []ValidationError{validationWarning(AmbiguousTypeCode, table, column.Name, fmt.Sprintf(
+		"type %s of column %s is the logical type mapped to %s, write %s for the driver type",
+		column.Type, column.Name, resolved, native,
+	))}
+}
+
+// validateRenamedFrom checks the previous name of a table, or of a column
+// when column is set.
+func validateRenamedFrom(table string, column string, renamedFrom string, name string) []ValidationError {
+	if renamedFrom == "" {
+		return nil
+	}
+	errs := make([]ValidationError, 0)
+	if err := utils.ValidateSQLName(renamedFrom); err != nil {
+		errs = append(errs, validationError(InvalidNameCode, table, column, fmt.Sprintf("invalid previous name: %s of %s (%s)", renamedFrom, name, err.Display())))
+	}
+	if renamedFrom == name {
+		errs = append(errs, validationError(InvalidRenameCode, table, column, fmt.Sprintf("%s cannot be renamed from itself", name)))
+	}
+	return errs
+}
+
+// validateTableConstraints checks the constraints declared on a table and
+// that it has at most one primary key. A primary key on a single column has
+// to be declared on the column.
+func validateTableConstraints(table schema.Table, columnNames map[string]bool) []ValidationError {
+	errs := make([]ValidationError, 0)
+	primaryKeys := 0
+	for _, column := range table.Columns {
+		if schema.HasConstraint(column.Constraints, schema.PrimaryKeyConstraint{}) {
+			primaryKeys++
+		}
+	}
+	constraintNames := make(map[string]bool)
+	for _, constraint := range table.Constraints {
+		if constraintNames[constraint.Name] {
+			errs = append(errs, validationError(DuplicateNameCode, table.Name, "", fmt.Sprintf("duplicate constraint name: %s in table %s", constraint.Name, table.Name)))
+		}
+		constraintNames[constraint.Name] = true
+		err := utils.ValidateSQLName(constraint.Name)
+		if err != nil {
+			errs = append(errs, validationError(InvalidNameCode, table.Name, "", fmt.Sprintf("invalid constraint name: %s (%s)", constraint.Name, err.Display())))
+		}
+		switch constraint.Type {
+		case schema.PrimaryKeyConstraintType:
+			primaryKeys++
+			if len(constraint.Columns) == 1 {
+				errs = append(errs, validationError(InvalidConstraintCode, table.Name, constraint.Columns[0], fmt.Sprintf("primary key %s has a single column, declare it on column %s of table %s", constraint.Name, constraint.Columns[0], table.Name)))
+			}
+		case schema.UniqueConstraintType, schema.ForeignKeyConstraintType:
+		case schema.CheckConstraintType:
+			if constraint.Expression == "" {
+				errs = append(errs, validationError(InvalidConstraintCode, table.Name, "", fmt.Sprintf("check constraint %s requires an expression", constraint.Name)))
+			}
+			continue
+		default:
+			errs = append(errs, validationError(InvalidConstraintCode, table.Name, "", fmt.Sprintf("invalid type %s for constraint %s in table %s", constraint.Type, constraint.Name, table.Name)))
+			continue
+		}
+		if len(constraint.Columns) == 0 {
+			errs = append(errs, validationError(InvalidConstraintCode, table.Name, "", fmt.Sprintf("constraint %s must have at least one column", constraint.Name)))
+		}
+		for _, column := range constraint.Columns {
+			if !columnNames[column] {
+				errs = append(errs, validationError(UnknownColumnCode, table.Name, column, fmt.Sprintf("constraint %s references unknown column %s in table %s", constraint.Name, column, table.Name)))
+			}
+		}
+	}
+	if primaryKeys > 1 {
+		errs = append(errs, validationError(MultiplePrimaryKeysCode, table.Name, "", fmt.Sprintf("table %s has more than one primary key", table.Name)))
+	}
+	return errs
+}
+
+// validateReferences checks that the tables and columns referenced by
+// foreign keys exist in the schema.
+func validateReferences(tables []schema.Table) []ValidationError {
+	errs := make([]ValidationError, 0)
+	columns := make(map[string]map[string]bool, len(tables))
+	for _, table := range tables {
+		columns[table.Name] = make(map[string]bool, len(table.Columns))
+		for _, column := range table.Columns {
+			columns[table.Name][column.Name] = true
+		}
+	}
+	checkReference := func(table string, column string, source string, referencedTable string, referencedColumns []string) {
+		tableColumns, ok := columns[referencedTable]
+		if !ok {
+			errs = append(errs, validationError(UnknownTableCode, table, column, fmt.Sprintf("%s references unknown table %s", source, referencedTable)))
+			return
+		}
+		for _, referencedColumn := range referencedColumns {
+			if !tableColumns[referencedColumn] {
+				errs = append(errs, validationError(UnknownColumnCode, table, column, fmt.Sprintf("%s references unknown column %s.%s", source, referencedTable, referencedColumn)))
+			}
+		}
+	}
+	for _, table := range tables {
+		for _, column := range table.Columns {
+			for _, constraint := range column.Constraints {
+				if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+					source := fmt.Sprintf("foreign key of column %s.%s", table.Name, column.Name)
+					checkReference(table.Name, column.Name, source, fk.ReferencedTable, []string{fk.ReferencedColumn})
+				}
+			}
+		}
+		for _, constraint := range table.Constraints {
+			if constraint.Type != schema.ForeignKeyConstraintType {
+				continue
+			}
+			source := fmt.Sprintf("foreign key %s of table %s", constraint.Name, table.Name)
+			if len(constraint.ReferencedColumns) != len(constraint.Columns) {
+				errs = append(errs, validationError(InvalidConstraintCode, table.Name, "", fmt.Sprintf("%s has %d column(s) but references %d", source, len(constraint.Columns), len(constraint.ReferencedColumns))))
+			}
+			checkReference(table.Name, "", source, constraint.ReferencedTable, constraint.ReferencedColumns)
+		}
+	}
+	return errs
+}
+
+// validateIndex checks an index of a table against the names of the indexes
+// checked before it, within the table or the database depending on the
+// driver. Drivers reading unique indexes back as unique constraints require
+// them to be declared as such. driver is nil when the driver is invalid.
+func validateIndex(driver drivers.Driver, table schema.Table, index schema.Index, columnNames map[string]bool, indexNames map[string]bool) []ValidationError {
+	errs := make([]ValidationError, 0)
+	if indexNames[index.Name] {
+		errs = append(errs, validationError(DuplicateNameCode, table.Name, "", fmt.Sprintf("duplicate index name: %s", index.Name)))
+	}
+	indexNames[index.Name] = true
+	err := utils.ValidateSQLName(index.Name)
+	if err != nil {
+		errs = append(errs, validationError(InvalidNameCode, table.Name, "", fmt.Sprintf("invalid index name: %s (%s)", index.Name, err.Display())))
+	}
+	if len(index.Columns) == 0 {
+		errs = append(errs, validationError(InvalidIndexCode, table.Name, "", fmt.Sprintf("index %s must have at least one column", index.Name)))
+	}
+	if index.Unique && driver != nil && driver.UniqueIndexesAsConstraints() {
+		errs = append(errs, validationError(InvalidIndexCode, table.Name, "", fmt.Sprintf("unique index %s must be declared as a unique constraint of table %s", index.Name, table.Name)))
+	}
+	for _, column := range index.Columns {
+		if !columnNames[column] {
+			errs = append(errs, validationError(UnknownColumnCode, table.Name, column, fmt.Sprintf("index %s references unknown column %s in table %s", index.Name, column, table.Name)))
 		}
 	}
 	return errs
@@ -0,0 +1,266 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestValidate(t *testing.T) {
+	users := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+			{Name: "name", Type: "TEXT"},
+		},
+	}
+	tests := []struct {
+		name   string
+		tables []schema.Table
+		want   []string
+	}{
+		{
+			name: "valid",
+			tables: []schema.Table{users, {
+				Name: "memberships",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "INTEGER", Constraints: schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id"}}},
+					{Name: "group_id", Type: "INTEGER"},
+				},
+				Constraints: []schema.TableConstraint{
+					{Name: "memberships_pkey", Type: schema.PrimaryKeyConstraintType, Columns: []string{"user_id", "group_id"}},
+				},
+				Indexes: []schema.Index{{Name: "memberships_group_id_idx", Columns: []string{"group_id"}}},
+			}},
+			want: []string{},
+		},
+		{
+			name: "logical types",
+			tables: []schema.Table{{
+				Name: "prices",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int64", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+					{Name: "amount", Type: "decimal(10,2)"},
+					{Name: "label", Type: "string(1,2)"},
+				},
+			}},
+			want: []string{"invalid data type: string(1,2) for column label"},
+		},
+		{
+			name: "invalid renames",
+			tables: []schema.Table{{
+				Name:        "people",
+				RenamedFrom: "people",
+				Columns: []schema.Column{
+					{Name: "id", Type: "INTEGER", RenamedFrom: "1d"},
+				},
+			}},
+			want: []string{
+				"people cannot be renamed from itself",
+				"invalid previous name: 1d of id (cannot start with a digit)",
+			},
+		},
+		{
+			name: "invalid references",
+			tables: []schema.Table{users, {
+				Name: "posts",
+				Columns: []schema.Column{
+					{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+					{Name: "author_id", Type: "INTEGER", Constraints: schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: "authors", ReferencedColumn: "id"}}},
+				},
+				Constraints: []schema.TableConstraint{
+					{Name: "posts_pkey", Type: schema.PrimaryKeyConstraintType, Columns: []string{"id", "author_id"}},
+					{Name: "posts_user_fkey", Type: schema.ForeignKeyConstraintType, Columns: []string{"author_id"}, ReferencedTable: "users", ReferencedColumns: []string{"uid"}},
+					{Name: "posts_key", Type: schema.UniqueConstraintType, Columns: []string{"title"}},
+				},
+				Indexes: []schema.Index{{Name: "posts_idx", Columns: []string{"slug"}}},
+			}},
+			want: []string{
+				"constraint posts_key references unknown column title in table posts",
+				"table posts has more than one primary key",
+				"index posts_idx references unknown column slug in table posts",
+				"foreign key of column posts.author_id references unknown table authors",
+				"foreign key posts_user_fkey of table posts references unknown column users.uid",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &SqlDatabase{Name: "test", DriverType: "sqlite", Tables: tt.tables}
+			got := make([]string, 0)
+			for _, err := range database.Validate() {
+				got = append(got, err.Display())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortedTables(t *testing.T) {
+	tables := []schema.Table{
+		{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}, schema.NotNullConstraint{}}},
+				{Name: "email", Type: "TEXT"},
+			},
+			Indexes: []schema.Index{
+				{Name: "users_name_idx", Columns: []string{"name"}},
+				{Name: "users_email_idx", Columns: []string{"email"}},
+			},
+		},
+		{
+			Name:    "posts",
+			Columns: []schema.Column{{Name: "id", Type: "INTEGER"}},
+			Constraints: []schema.TableConstraint{
+				{Name: "posts_title_check", Type: schema.CheckConstraintType, Expression: "title <> ''"},
+				{Name: "posts_id_check", Type: schema.CheckConstraintType, Expression: "id > 0"},
+			},
+		},
+	}
+	expected := []schema.Table{
+		{
+			Name:    "posts",
+			Columns: []schema.Column{{Name: "id", Type: "INTEGER"}},
+			Constraints: []schema.TableConstraint{
+				{Name: "posts_id_check", Type: schema.CheckConstraintType, Expression: "id > 0"},
+				{Name: "posts_title_check", Type: schema.CheckConstraintType, Expression: "title <> ''"},
+			},
+		},
+		{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.PrimaryKeyConstraint{}}},
+				{Name: "email", Type: "TEXT"},
+			},
+			Indexes: []schema.Index{
+				{Name: "users_email_idx", Columns: []string{"email"}},
+				{Name: "users_name_idx", Columns: []string{"name"}},
+			},
+		},
+	}
+	sorted := sortedTables(tables)
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Expected %v, got %v", expected, sorted)
+	}
+	if tables[0].Name != "users" || tables[0].Indexes[0].Name != "users_name_idx" {
+		t.Errorf("Expected the tables to be left untouched, got %v", tables)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	database := &SqlDatabase{
+		DriverType: drivers.SqliteDriverType,
+		Name:       "app",
+		Tables:     []schema.Table{{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}},
+	}
+	want := "Database: app\nDriver: sqlite\nTables:\nTable: users\n  Column: id, Type: INTEGER\n"
+	if got := database.Describe(); got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+	database.driver = drivers.GetDriver(drivers.SqliteDriverType)
+	want = "Database: app\nDriver: sqlite\nVersion: 3.35.0\nTables:\nTable: users\n  Column: id, Type: INTEGER\n"
+	if got := database.Describe(); got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateLogicalTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		driverType drivers.DriverType
+		columnType schema.DataType
+		want       []ValidationError
+	}{
+		{
+			name:       "logical type mapped to another postgres type",
+			driverType: drivers.PostgresDriverType,
+			columnType: "json",
+			want: []ValidationError{{
+				Code:    AmbiguousTypeCode,
+				Table:   "users",
+				Column:  "data",
+				Message: "type json of column data is the logical type mapped to JSONB, write JSON for the driver type",
+				Warning: true,
+			}},
+		},
+		{
+			name:       "logical type mapped to another mysql type",
+			driverType: drivers.MysqlDriverType,
+			columnType: "timestamp",
+			want: []ValidationError{{
+				Code:    AmbiguousTypeCode,
+				Table:   "users",
+				Column:  "data",
+				Message: "type timestamp of column data is the logical type mapped to DATETIME, write TIMESTAMP for the driver type",
+				Warning: true,
+			}},
+		},
+		{
+			name:       "logical type mapped to the driver type",
+			driverType: drivers.PostgresDriverType,
+			columnType: "timestamp",
+			want:       []ValidationError{},
+		},
+		{
+			name:       "logical type without driver type",
+			driverType: drivers.MysqlDriverType,
+			columnType: "int64",
+			want:       []ValidationError{},
+		},
+		{
+			name:       "driver type",
+			driverType: drivers.PostgresDriverType,
+			columnType: "JSON",
+			want:       []ValidationError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &SqlDatabase{Name: "test", DriverType: tt.driverType, Tables: []schema.Table{{
+				Name:    "users",
+				Columns: []schema.Column{{Name: "data", Type: tt.columnType}},
+			}}}
+			got := database.Validate()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+			if len(WithoutWarnings(got)) != 0 {
+				t.Errorf("WithoutWarnings() = %v, want none", WithoutWarnings(got))
+			}
+		})
+	}
+}
+
+func TestValidateIndexNameScope(t *testing.T) {
+	tables := []schema.Table{
+		{Name: "users", Columns: []schema.Column{{Name: "id", Type: "int32"}}, Indexes: []schema.Index{{Name: "id_idx", Columns: []string{"id"}}}},
+		{Name: "posts", Columns: []schema.Column{{Name: "id", Type: "int32"}}, Indexes: []schema.Index{{Name: "id_idx", Columns: []string{"id"}}}},
+	}
+	tests := []struct {
+		driverType drivers.DriverType
+		want       []string
+	}{
+		{driverType: drivers.PostgresDriverType, want: []string{"duplicate index name: id_idx"}},
+		{driverType: drivers.SqliteDriverType, want: []string{"duplicate index name: id_idx"}},
+		{driverType: drivers.MysqlDriverType, want: []string{}},
+		{driverType: drivers.SqlserverDriverType, want: []string{}},
+		{driverType: drivers.CockroachdbDriverType, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.driverType), func(t *testing.T) {
+			database := &SqlDatabase{Name: "test", DriverType: tt.driverType, Tables: tables}
+			got := make([]string, 0)
+			for _, err := range database.Validate() {
+				got = append(got, err.Display())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+	"github.com/yassirdeveloper/migrater/internal/utils"
+)
+
+// cockroachdbDriver manages CockroachDB clusters, which speak the postgres
+// wire protocol. It reuses the connection and most of the introspection and
+// DDL of the postgres driver, and overrides the parts where CockroachDB
+// differs: its types, hidden columns and online schema changes.
+type cockroachdbDriver struct {
+	*postgresDriver
+}
+
+// Connect reads the CockroachDB version from version(), server_version_num
+// being the version of postgres CockroachDB is compatible with.
+func (d *cockroachdbDriver) Connect(dsn utils.DSN) errors.Error {
+	if err := d.postgresDriver.Connect(dsn); err != nil {
+		return err
+	}
+	var version string
+	if err := d.conn.QueryRow("SELECT version()").Scan(&version); err != nil {
+		return errors.New(fmt.Sprintf("Could not query server version!\n%s", err))
+	}
+	parsed, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	d.version = parsed
+	return nil
+}
+
+// GetTable reads the column types from crdb_sql_type, information_schema
+// reporting the postgres names of the types, e.g. integer for INT4 while
+// INTEGER stands for INT8 in CockroachDB. The hidden rowid column added to
+// tables without a primary key is left out, along with its primary key.
+func (d *cockroachdbDriver) GetTable(tableName string) (schema.Table, errors.Error) {
+	table, err := d.postgresDriver.GetTable(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	query := `SELECT column_name::text, crdb_sql_type::text, is_hidden::text
+	FROM information_schema.columns
+	WHERE table_schema = 'public' AND table_name = $1`
+	rows, err_ := d.conn.Query(query, tableName)
+	if err_ != nil {
+		return schema.Table{}, errors.NewUnexpectedError(err_)
+	}
+	defer rows.Close()
+
+	types := make(map[string]schema.DataType)
+	hidden := make(map[string]bool)
+	for rows.Next() {
+		var name, sqlType, isHidden string
+		if err := rows.Scan(&name, &sqlType, &isHidden); err != nil {
+			return schema.Table{}, errors.NewUnexpectedError(err)
+		}
+		types[name] = schema.DataType(sqlType)
+		hidden[name] = isHidden == "YES"
+	}
+	if err := rows.Err(); err != nil {
+		return schema.Table{}, errors.NewUnexpectedError(err)
+	}
+	table.Columns = cockroachdbColumns(table.Columns, types, hidden)
+	return table, nil
+}
+
+// cockroachdbColumns gives the columns read by the postgres driver their
+// CockroachDB types and leaves out the hidden ones.
+func cockroachdbColumns(columns []schema.Column, types map[string]schema.DataType, hidden map[string]bool) []schema.Column {
+	visible := make([]schema.Column, 0, len(columns))
+	for _, column := range columns {
+		if hidden[column.Name] {
+			continue
+		}
+		if columnType, ok := types[column.Name]; ok {
+			column.Type = columnType
+		}
+		visible = append(visible, column)
+	}
+	return visible
+}
+
+var cockroachdbDriverInstance = &cockroachdbDriver{
+	postgresDriver: &postgresDriver{
+		version: Version{Major: 23, Minor: 1, Patch: 0},
+		typeVersions: map[schema.DataType]Version{
+			"ENUM":      {Major: 20, Minor: 2, Patch: 0},
+			"GEOMETRY":  {Major: 20, Minor: 2, Patch: 0},
+			"GEOGRAPHY": {Major: 20, Minor: 2, Patch: 0},
+			"TSVECTOR":  {Major: 23, Minor: 1, Patch: 0},
+			"TSQUERY":   {Major: 23, Minor: 1, Patch: 0},
+		},
+		dataTypes: []schema.DataType{
+			"BOOL",
+			"INT2",
+			"INT4",
+			"INT8",
+			"FLOAT4",
+			"FLOAT8",
+			"DECIMAL",
+			"STRING",
+			"CHAR",
+			"NAME",
+			"BYTES",
+			"BIT",
+			"VARBIT",
+			"DATE",
+			"TIME",
+			"TIMETZ",
+			"TIMESTAMP",
+			"TIMESTAMPTZ",
+			"INTERVAL",
+			"UUID",
+			"INET",
+			"JSONB",
+			"OID",
+			"ENUM",
+			"GEOMETRY",
+			"GEOGRAPHY",
+			"TSVECTOR",
+			"TSQUERY",
+		},
+	},
+}
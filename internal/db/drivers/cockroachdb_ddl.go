@@ -0,0 +1,144 @@
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// SupportsTransactionalDDL is false since CockroachDB runs schema changes
+// asynchronously once their transaction commits, a failing schema change
+// does not roll back the statements before it.
+func (d *cockroachdbDriver) SupportsTransactionalDDL() bool {
+	return false
+}
+
+func (d *cockroachdbDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return cockroachdbTypeRules.resolveType(t)
+}
+
+func (d *cockroachdbDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return cockroachdbTypeRules.normalizeType(t)
+}
+
+// AddConstraint replaces the primary key with ALTER PRIMARY KEY, every
+// CockroachDB table having one on its hidden rowid column when none is
+// declared.
+func (d *cockroachdbDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	if _, ok := constraint.(schema.PrimaryKeyConstraint); ok {
+		return d.alterPrimaryKey(table, []string{column.Name})
+	}
+	return d.postgresDriver.AddConstraint(table, column, constraint)
+}
+
+// DropConstraint drops unique constraints through their index, which
+// CockroachDB requires.
+func (d *cockroachdbDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	switch constraint.(type) {
+	case schema.PrimaryKeyConstraint:
+		return nil, d.primaryKeyDropError(table)
+	case schema.UniqueConstraint:
+		return []string{d.dropIndex(table, constraintName(table, column, "key"), true)}, nil
+	}
+	return d.postgresDriver.DropConstraint(table, column, constraint)
+}
+
+func (d *cockroachdbDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	if constraint.Type == schema.PrimaryKeyConstraintType {
+		return d.alterPrimaryKey(table, constraint.Columns)
+	}
+	return d.postgresDriver.AddTableConstraint(table, constraint)
+}
+
+func (d *cockroachdbDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	switch constraint.Type {
+	case schema.PrimaryKeyConstraintType:
+		return nil, d.primaryKeyDropError(table)
+	case schema.UniqueConstraintType:
+		return []string{d.dropIndex(table, constraint.Name, true)}, nil
+	}
+	return d.postgresDriver.DropTableConstraint(table, constraint)
+}
+
+// DropIndex names the index with its table, index names being only unique
+// per table in CockroachDB.
+func (d *cockroachdbDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{d.dropIndex(table, index.Name, false)}, nil
+}
+
+// IndexNamesPerTable is true, unlike postgres, indexes are named within
+// their table, e.g. users@users_email_idx.
+func (d *cockroachdbDriver) IndexNamesPerTable() bool {
+	return true
+}
+
+// cockroachdbAlterPrimaryKeySince is the version introducing ALTER PRIMARY
+// KEY.
+var cockroachdbAlterPrimaryKeySince = Version{Major: 20, Minor: 1, Patch: 0}
+
+func (d *cockroachdbDriver) alterPrimaryKey(table schema.Table, columns []string) ([]string, errors.Error) {
+	if !d.version.AtLeast(cockroachdbAlterPrimaryKeySince) {
+		return nil, unsupportedFeature("cockroachdb", d.version, "ALTER PRIMARY KEY", cockroachdbAlterPrimaryKeySince)
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ALTER PRIMARY KEY USING COLUMNS (%s)", d.QuoteIdentifier(table.Name), quoteIdentifiers(d, columns))}, nil
+}
+
+func (d *cockroachdbDriver) dropIndex(table schema.Table, name string, cascade bool) string {
+	sql := fmt.Sprintf("DROP INDEX %s@%s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(name))
+	if cascade {
+		sql += " CASCADE"
+	}
+	return sql
+}
+
+func (d *cockroachdbDriver) primaryKeyDropError(table schema.Table) errors.Error {
+	return errors.New(fmt.Sprintf("cockroachdb cannot drop the primary key of %s, declare the new primary key instead", table.Name))
+}
+
+// cockroachdbLogicalTypes maps logical types to CockroachDB types, spelled
+// as they are introspected.
+var cockroachdbLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INT4"},
+	schema.Int64Type:     {plain: "INT8"},
+	schema.StringType:    {plain: "STRING", parameterized: "STRING(%s)"},
+	schema.DecimalType:   {plain: "DECIMAL", parameterized: "DECIMAL(%s)"},
+	schema.BoolType:      {plain: "BOOL"},
+	schema.TimestampType: {plain: "TIMESTAMP"},
+	schema.UUIDType:      {plain: "UUID"},
+	schema.JSONType:      {plain: "JSONB"},
+	schema.BytesType:     {plain: "BYTES"},
+}
+
+// cockroachdbTypeRules follow the CockroachDB defaults, where INT and
+// INTEGER are 64 bit integers and VARCHAR and TEXT are STRING.
+var cockroachdbTypeRules = typeRules{
+	logical: cockroachdbLogicalTypes,
+	aliases: map[string]schema.DataType{
+		"INT":                         "INT8",
+		"INTEGER":                     "INT8",
+		"INT64":                       "INT8",
+		"BIGINT":                      "INT8",
+		"SMALLINT":                    "INT2",
+		"REAL":                        "FLOAT4",
+		"FLOAT":                       "FLOAT8",
+		"DOUBLE PRECISION":            "FLOAT8",
+		"BOOLEAN":                     "BOOL",
+		"NUMERIC":                     "DECIMAL",
+		"DEC":                         "DECIMAL",
+		"TEXT":                        "STRING",
+		"VARCHAR":                     "STRING",
+		"CHARACTER VARYING":           "STRING",
+		"CHARACTER":                   "CHAR",
+		"BPCHAR":                      "CHAR",
+		"BYTEA":                       "BYTES",
+		"BLOB":                        "BYTES",
+		"JSON":                        "JSONB",
+		"BIT VARYING":                 "VARBIT",
+		"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+		"TIMESTAMP WITH TIME ZONE":    "TIMESTAMPTZ",
+		"TIME WITHOUT TIME ZONE":      "TIME",
+		"TIME WITH TIME ZONE":         "TIMETZ",
+	},
+	arrays: true,
+}
@@ -0,0 +1,25 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestCockroachdbColumns(t *testing.T) {
+	columns := []schema.Column{
+		{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "name", Type: "TEXT"},
+		{Name: "rowid", Type: "BIGINT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.PrimaryKeyConstraint{}}},
+	}
+	types := map[string]schema.DataType{"id": "INT4", "name": "STRING", "rowid": "INT8"}
+	hidden := map[string]bool{"rowid": true}
+	want := []schema.Column{
+		{Name: "id", Type: "INT4", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "name", Type: "STRING"},
+	}
+	if got := cockroachdbColumns(columns, types, hidden); !reflect.DeepEqual(got, want) {
+		t.Errorf("cockroachdbColumns() = %+v, want %+v", got, want)
+	}
+}
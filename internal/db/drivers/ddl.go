@@ -0,0 +1,234 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// Dialect renders the DDL statements of a driver. The methods rendering a
+// change return the statements to execute, in order.
+type Dialect interface {
+	// SupportsTransactionalDDL reports whether DDL statements can be rolled
+	// back as part of a transaction.
+	SupportsTransactionalDDL() bool
+	QuoteIdentifier(string) string
+	QuoteLiteral(string) string
+	// ResolveType maps a logical type to the type of the driver.
+	ResolveType(schema.DataType) (schema.DataType, errors.Error)
+	// NormalizeType parses a type into its canonical form, reporting
+	// whether the driver knows it.
+	NormalizeType(schema.DataType) (schema.ParsedType, bool)
+	// AutoIncrementClause renders the auto increment of a column
+	// definition, empty when the dialect declares it otherwise.
+	AutoIncrementClause() string
+	// PrimaryKeyClause renders the inline primary key of a column.
+	PrimaryKeyClause(schema.Column) string
+	// UniqueConstraintName returns the name the dialect gives to the unique
+	// constraint of a column.
+	UniqueConstraintName(schema.Table, schema.Column) string
+	// LargeTextType is the type holding the largest texts.
+	LargeTextType() schema.DataType
+	CreateTable(schema.Table) ([]string, errors.Error)
+	DropTable(schema.Table) ([]string, errors.Error)
+	RenameTable(string, schema.Table) ([]string, errors.Error)
+	AddColumn(schema.Table, schema.Column) ([]string, errors.Error)
+	DropColumn(schema.Table, schema.Column) ([]string, errors.Error)
+	RenameColumn(schema.Table, string, schema.Column) ([]string, errors.Error)
+	AlterColumnType(schema.Table, schema.Column) ([]string, errors.Error)
+	AddConstraint(schema.Table, schema.Column, schema.Constraint) ([]string, errors.Error)
+	DropConstraint(schema.Table, schema.Column, schema.Constraint) ([]string, errors.Error)
+	AddTableConstraint(schema.Table, schema.TableConstraint) ([]string, errors.Error)
+	DropTableConstraint(schema.Table, schema.TableConstraint) ([]string, errors.Error)
+	CreateIndex(schema.Table, schema.Index) ([]string, errors.Error)
+	DropIndex(schema.Table, schema.Index) ([]string, errors.Error)
+	// IndexNamesPerTable reports whether index names only have to be
+	// unique within their table rather than the whole database.
+	IndexNamesPerTable() bool
+	// UniqueIndexesAsConstraints reports whether unique indexes are read
+	// back as unique constraints.
+	UniqueIndexesAsConstraints() bool
+}
+
+// TableRebuilder is implemented by dialects which cannot apply some changes
+// with ALTER TABLE and rebuild the table instead: a new table is created
+// with the desired definition, the rows are copied to it and it replaces
+// the live table. Foreign keys are not enforced while tables are rebuilt,
+// and checked once they are.
+type TableRebuilder interface {
+	// RebuildTable rebuilds the live table previousName into the desired
+	// table, copying the given columns. The live indexes of the table which
+	// are neither desired nor in droppedIndexes are kept.
+	RebuildTable(previousName string, table schema.Table, columns []string, droppedIndexes []string) ([]string, errors.Error)
+	// ForeignKeyCheck returns a query listing the rows of a table which
+	// violate its foreign keys.
+	ForeignKeyCheck(table schema.Table) string
+	// ForeignKeys returns the statement enabling or disabling the
+	// enforcement of foreign keys, which must run outside of a transaction.
+	ForeignKeys(enabled bool) string
+}
+
+func quoteIdentifier(name string, quote string) string {
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// constraintName returns the name given to a single column constraint. It
+// follows the postgres naming convention so that constraints created by
+// postgres itself can be dropped by name.
+func constraintName(table schema.Table, column schema.Column, suffix string) string {
+	return fmt.Sprintf("%s_%s_%s", table.Name, column.Name, suffix)
+}
+
+// renameColumn renames a column of a table, keeping its type, data and
+// constraints.
+func renameColumn(d Dialect, table schema.Table, previousName string, column schema.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(previousName), d.QuoteIdentifier(column.Name))
+}
+
+func quoteIdentifiers(d Dialect, names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, d.QuoteIdentifier(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func referencesClause(d Dialect, fk schema.ForeignKeyConstraint) string {
+	clause := fmt.Sprintf("REFERENCES %s (%s)", d.QuoteIdentifier(fk.ReferencedTable), d.QuoteIdentifier(fk.ReferencedColumn))
+	return clause + referentialActions(fk.OnDelete, fk.OnUpdate)
+}
+
+func referentialActions(onDelete string, onUpdate string) string {
+	actions := ""
+	if onDelete != "" {
+		actions += " ON DELETE " + onDelete
+	}
+	if onUpdate != "" {
+		actions += " ON UPDATE " + onUpdate
+	}
+	return actions
+}
+
+// columnDefault returns the default value of a column, declared either as a
+// constraint or in its default field.
+func columnDefault(column schema.Column) (string, bool) {
+	for _, constraint := range column.GetConstraints() {
+		if c, ok := constraint.(schema.DefaultConstraint); ok {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
+
+// tableConstraintClause renders a table constraint as declared in CREATE
+// TABLE or ALTER TABLE ADD.
+func tableConstraintClause(d Dialect, constraint schema.TableConstraint) string {
+	columns := quoteIdentifiers(d, constraint.Columns)
+	var clause string
+	switch constraint.Type {
+	case schema.PrimaryKeyConstraintType:
+		clause = fmt.Sprintf("PRIMARY KEY (%s)", columns)
+	case schema.UniqueConstraintType:
+		clause = fmt.Sprintf("UNIQUE (%s)", columns)
+	case schema.ForeignKeyConstraintType:
+		clause = fmt.Sprintf(
+			"FOREIGN KEY (%s) REFERENCES %s (%s)%s",
+			columns,
+			d.QuoteIdentifier(constraint.ReferencedTable),
+			quoteIdentifiers(d, constraint.ReferencedColumns),
+			referentialActions(constraint.OnDelete, constraint.OnUpdate),
+		)
+	case schema.CheckConstraintType:
+		clause = fmt.Sprintf("CHECK (%s)", constraint.Expression)
+	}
+	return fmt.Sprintf("CONSTRAINT %s %s", d.QuoteIdentifier(constraint.Name), clause)
+}
+
+func unsupportedTableConstraint(table schema.Table, constraint schema.TableConstraint) errors.Error {
+	return errors.New(fmt.Sprintf("unsupported constraint type %s for constraint %s on %s", constraint.Type, constraint.Name, table.Name))
+}
+
+func foreignKeyClause(d Dialect, table schema.Table, column schema.Column, fk schema.ForeignKeyConstraint) string {
+	return fmt.Sprintf(
+		"CONSTRAINT %s FOREIGN KEY (%s) %s",
+		d.QuoteIdentifier(constraintName(table, column, "fkey")),
+		d.QuoteIdentifier(column.Name),
+		referencesClause(d, fk),
+	)
+}
+
+// createTable renders a CREATE TABLE statement with the given column
+// definitions, foreign keys are declared as named table constraints along
+// with the constraints of the table.
+func createTable(d Dialect, table schema.Table, columnDefinition func(schema.Table, schema.Column) string) string {
+	definitions := make([]string, 0, len(table.Columns))
+	foreignKeys := make([]string, 0)
+	for _, column := range table.Columns {
+		definitions = append(definitions, "  "+columnDefinition(table, column))
+		for _, constraint := range column.Constraints {
+			if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+				foreignKeys = append(foreignKeys, "  "+foreignKeyClause(d, table, column, fk))
+			}
+		}
+	}
+	definitions = append(definitions, foreignKeys...)
+	for _, constraint := range table.Constraints {
+		definitions = append(definitions, "  "+tableConstraintClause(d, constraint))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)", d.QuoteIdentifier(table.Name), strings.Join(definitions, ",\n"))
+}
+
+// inlineConstraints renders the column constraints that can be declared
+// inline in a column definition. Key constraints are skipped when withKeys
+// is false.
+func inlineConstraints(d Dialect, table schema.Table, column schema.Column, withKeys bool) string {
+	clauses := ""
+	for _, constraint := range column.GetConstraints() {
+		switch c := constraint.(type) {
+		case schema.NotNullConstraint:
+			clauses += " NOT NULL"
+		case schema.DefaultConstraint:
+			clauses += " DEFAULT " + c.Value
+		case schema.PrimaryKeyConstraint:
+			if withKeys {
+				clauses += d.PrimaryKeyClause(column)
+			}
+		case schema.UniqueConstraint:
+			if withKeys {
+				clauses += " UNIQUE"
+			}
+		case schema.AutoIncrementConstraint:
+			clauses += d.AutoIncrementClause()
+		case schema.CheckConstraint:
+			if withKeys {
+				name := d.QuoteIdentifier(constraintName(table, column, "check"))
+				clauses += fmt.Sprintf(" CONSTRAINT %s CHECK (%s)", name, c.Expression)
+			}
+		}
+	}
+	return clauses
+}
+
+// createIndex renders a CREATE INDEX statement, the method clause is
+// rendered by the caller since its position depends on the dialect.
+func createIndex(d Dialect, table schema.Table, index schema.Index, method string) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	sql := fmt.Sprintf("CREATE %sINDEX %s ON %s%s (%s)", unique, d.QuoteIdentifier(index.Name), d.QuoteIdentifier(table.Name), method, quoteIdentifiers(d, index.Columns))
+	if index.Where != "" {
+		sql += " WHERE " + index.Where
+	}
+	return sql
+}
+
+func unsupportedConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) errors.Error {
+	return errors.New(fmt.Sprintf("unsupported constraint %s on %s.%s", constraint.Name(), table.Name, column.Name))
+}
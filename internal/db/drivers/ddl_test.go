@@ -0,0 +1,707 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+var ddlTestTable = schema.Table{
+	Name: "posts",
+	Columns: []schema.Column{
+		{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}, schema.AutoIncrementConstraint{}}},
+		{Name: "title", Type: "VARCHAR(255)", Constraints: []schema.Constraint{schema.NotNullConstraint{}, schema.DefaultConstraint{Value: "''"}}},
+		{Name: "user_id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"}}},
+	},
+}
+
+func TestCreateTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    []string
+	}{
+		{
+			name:    "mysql",
+			dialect: mysqlDriverInstance,
+			want: []string{"CREATE TABLE `posts` (\n" +
+				"  `id` INTEGER PRIMARY KEY AUTO_INCREMENT,\n" +
+				"  `title` VARCHAR(255) NOT NULL DEFAULT '',\n" +
+				"  `user_id` INTEGER,\n" +
+				"  CONSTRAINT `posts_user_id_fkey` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE\n" +
+				")"},
+		},
+		{
+			name:    "postgres",
+			dialect: postgresDriverInstance,
+			want: []string{"CREATE TABLE \"posts\" (\n" +
+				"  \"id\" INTEGER PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,\n" +
+				"  \"title\" VARCHAR(255) NOT NULL DEFAULT '',\n" +
+				"  \"user_id\" INTEGER,\n" +
+				"  CONSTRAINT \"posts_user_id_fkey\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\") ON DELETE CASCADE\n" +
+				")"},
+		},
+		{
+			name:    "sqlite",
+			dialect: sqliteDriverInstance,
+			want: []string{"CREATE TABLE \"posts\" (\n" +
+				"  \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n" +
+				"  \"title\" VARCHAR(255) NOT NULL DEFAULT '',\n" +
+				"  \"user_id\" INTEGER,\n" +
+				"  CONSTRAINT \"posts_user_id_fkey\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\") ON DELETE CASCADE\n" +
+				")"},
+		},
+		{
+			name:    "sqlserver",
+			dialect: sqlserverDriverInstance,
+			want: []string{"CREATE TABLE [posts] (\n" +
+				"  [id] INTEGER IDENTITY(1,1) CONSTRAINT [posts_pkey] PRIMARY KEY,\n" +
+				"  [title] VARCHAR(255) NOT NULL CONSTRAINT [posts_title_default] DEFAULT '',\n" +
+				"  [user_id] INTEGER,\n" +
+				"  CONSTRAINT [posts_user_id_fkey] FOREIGN KEY ([user_id]) REFERENCES [users] ([id]) ON DELETE CASCADE\n" +
+				")"},
+		},
+		{
+			name:    "cockroachdb",
+			dialect: cockroachdbDriverInstance,
+			want: []string{"CREATE TABLE \"posts\" (\n" +
+				"  \"id\" INTEGER PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,\n" +
+				"  \"title\" VARCHAR(255) NOT NULL DEFAULT '',\n" +
+				"  \"user_id\" INTEGER,\n" +
+				"  CONSTRAINT \"posts_user_id_fkey\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\") ON DELETE CASCADE\n" +
+				")"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dialect.CreateTable(ddlTestTable)
+			if err != nil {
+				t.Fatalf("CreateTable() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateTable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTableWithTableConstraints(t *testing.T) {
+	table := schema.Table{
+		Name: "post_tags",
+		Columns: []schema.Column{
+			{Name: "post_id", Type: "INTEGER"},
+			{Name: "tag_id", Type: "INTEGER"},
+			{Name: "position", Type: "INTEGER"},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "post_tags_pkey", Type: schema.PrimaryKeyConstraintType, Columns: []string{"post_id", "tag_id"}},
+			{Name: "post_tags_tag_fkey", Type: schema.ForeignKeyConstraintType, Columns: []string{"tag_id", "post_id"}, ReferencedTable: "tags", ReferencedColumns: []string{"id", "post_id"}, OnDelete: "CASCADE"},
+			{Name: "post_tags_position_key", Type: schema.UniqueConstraintType, Columns: []string{"post_id", "position"}},
+			{Name: "post_tags_position_check", Type: schema.CheckConstraintType, Expression: "position >= 0"},
+		},
+	}
+	want := []string{"CREATE TABLE \"post_tags\" (\n" +
+		"  \"post_id\" INTEGER,\n" +
+		"  \"tag_id\" INTEGER,\n" +
+		"  \"position\" INTEGER,\n" +
+		"  CONSTRAINT \"post_tags_pkey\" PRIMARY KEY (\"post_id\", \"tag_id\"),\n" +
+		"  CONSTRAINT \"post_tags_tag_fkey\" FOREIGN KEY (\"tag_id\", \"post_id\") REFERENCES \"tags\" (\"id\", \"post_id\") ON DELETE CASCADE,\n" +
+		"  CONSTRAINT \"post_tags_position_key\" UNIQUE (\"post_id\", \"position\"),\n" +
+		"  CONSTRAINT \"post_tags_position_check\" CHECK (position >= 0)\n" +
+		")"}
+	got, err := postgresDriverInstance.CreateTable(table)
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateTable() = %q, want %q", got, want)
+	}
+}
+
+func TestAlterStatements(t *testing.T) {
+	title := ddlTestTable.Columns[1]
+	tests := []struct {
+		name    string
+		render  func() ([]string, errors.Error)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "mysql alter type",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.AlterColumnType(ddlTestTable, title)
+			},
+			want: []string{"ALTER TABLE `posts` MODIFY COLUMN `title` VARCHAR(255) NOT NULL DEFAULT ''"},
+		},
+		{
+			name: "postgres alter type",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.AlterColumnType(ddlTestTable, title)
+			},
+			want: []string{"ALTER TABLE \"posts\" ALTER COLUMN \"title\" TYPE VARCHAR(255)"},
+		},
+		{
+			name: "sqlite alter type",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.AlterColumnType(ddlTestTable, title)
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql drop unique",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.DropConstraint(ddlTestTable, title, schema.UniqueConstraint{})
+			},
+			want: []string{"ALTER TABLE `posts` DROP INDEX `title`"},
+		},
+		{
+			name: "postgres add not null",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.AddConstraint(ddlTestTable, title, schema.NotNullConstraint{})
+			},
+			want: []string{"ALTER TABLE \"posts\" ALTER COLUMN \"title\" SET NOT NULL"},
+		},
+		{
+			name: "postgres drop foreign key",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.DropConstraint(ddlTestTable, ddlTestTable.Columns[2], ddlTestTable.Columns[2].Constraints[0])
+			},
+			want: []string{"ALTER TABLE \"posts\" DROP CONSTRAINT \"posts_user_id_fkey\""},
+		},
+		{
+			name: "sqlite add unique",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.AddConstraint(ddlTestTable, title, schema.UniqueConstraint{})
+			},
+			want: []string{"CREATE UNIQUE INDEX \"posts_title_key\" ON \"posts\" (\"title\")"},
+		},
+		{
+			name: "sqlite add not null column without default",
+			render: func() ([]string, errors.Error) {
+				column := schema.Column{Name: "body", Type: "TEXT", Constraints: []schema.Constraint{schema.NotNullConstraint{}}}
+				return sqliteDriverInstance.AddColumn(ddlTestTable, column)
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlite add not null column with default field",
+			render: func() ([]string, errors.Error) {
+				column := schema.Column{Name: "views", Type: "INTEGER", Default: "0", Constraints: []schema.Constraint{schema.NotNullConstraint{}}}
+				return sqliteDriverInstance.AddColumn(ddlTestTable, column)
+			},
+			want: []string{"ALTER TABLE \"posts\" ADD COLUMN \"views\" INTEGER NOT NULL DEFAULT 0"},
+		},
+		{
+			name: "sqlite drop foreign key column",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.DropColumn(ddlTestTable, ddlTestTable.Columns[2])
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql drop auto increment",
+			render: func() ([]string, errors.Error) {
+				id := schema.Column{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}}
+				return mysqlDriverInstance.DropConstraint(ddlTestTable, id, schema.AutoIncrementConstraint{})
+			},
+			want: []string{"ALTER TABLE `posts` MODIFY COLUMN `id` INTEGER"},
+		},
+		{
+			name: "postgres add auto increment",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.AddConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.AutoIncrementConstraint{})
+			},
+			want: []string{"ALTER TABLE \"posts\" ALTER COLUMN \"id\" ADD GENERATED BY DEFAULT AS IDENTITY"},
+		},
+		{
+			name: "sqlite add auto increment",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.AddConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.AutoIncrementConstraint{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "postgres create partial index",
+			render: func() ([]string, errors.Error) {
+				index := schema.Index{Name: "posts_title_idx", Columns: []string{"title", "user_id"}, Method: "gin", Where: "user_id IS NOT NULL"}
+				return postgresDriverInstance.CreateIndex(ddlTestTable, index)
+			},
+			want: []string{"CREATE INDEX \"posts_title_idx\" ON \"posts\" USING gin (\"title\", \"user_id\") WHERE user_id IS NOT NULL"},
+		},
+		{
+			name: "mysql create fulltext index",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Method: "fulltext"})
+			},
+			want: []string{"CREATE FULLTEXT INDEX `posts_title_idx` ON `posts` (`title`)"},
+		},
+		{
+			name: "mysql create hash index",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Unique: true, Method: "hash"})
+			},
+			want: []string{"CREATE UNIQUE INDEX `posts_title_idx` ON `posts` (`title`) USING HASH"},
+		},
+		{
+			name: "mysql create partial index",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Where: "title <> ''"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql drop index",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.DropIndex(ddlTestTable, schema.Index{Name: "posts_title_idx"})
+			},
+			want: []string{"DROP INDEX `posts_title_idx` ON `posts`"},
+		},
+		{
+			name: "sqlite create index with method",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Method: "hash"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "postgres add table constraint",
+			render: func() ([]string, errors.Error) {
+				constraint := schema.TableConstraint{Name: "posts_title_user_id_key", Type: schema.UniqueConstraintType, Columns: []string{"title", "user_id"}}
+				return postgresDriverInstance.AddTableConstraint(ddlTestTable, constraint)
+			},
+			want: []string{"ALTER TABLE \"posts\" ADD CONSTRAINT \"posts_title_user_id_key\" UNIQUE (\"title\", \"user_id\")"},
+		},
+		{
+			name: "mysql drop unique table constraint",
+			render: func() ([]string, errors.Error) {
+				constraint := schema.TableConstraint{Name: "posts_title_user_id_key", Type: schema.UniqueConstraintType, Columns: []string{"title", "user_id"}}
+				return mysqlDriverInstance.DropTableConstraint(ddlTestTable, constraint)
+			},
+			want: []string{"ALTER TABLE `posts` DROP INDEX `posts_title_user_id_key`"},
+		},
+		{
+			name: "sqlite add table constraint",
+			render: func() ([]string, errors.Error) {
+				constraint := schema.TableConstraint{Name: "posts_title_user_id_key", Type: schema.UniqueConstraintType, Columns: []string{"title", "user_id"}}
+				return sqliteDriverInstance.AddTableConstraint(ddlTestTable, constraint)
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql add column with foreign key",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.AddColumn(ddlTestTable, ddlTestTable.Columns[2])
+			},
+			want: []string{
+				"ALTER TABLE `posts` ADD COLUMN `user_id` INTEGER",
+				"ALTER TABLE `posts` ADD CONSTRAINT `posts_user_id_fkey` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE",
+			},
+		},
+		{
+			name: "mysql rename table",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.RenameTable("articles", ddlTestTable)
+			},
+			want: []string{"RENAME TABLE `articles` TO `posts`"},
+		},
+		{
+			name: "postgres rename table",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.RenameTable("articles", ddlTestTable)
+			},
+			want: []string{"ALTER TABLE \"articles\" RENAME TO \"posts\""},
+		},
+		{
+			name: "sqlite rename column",
+			render: func() ([]string, errors.Error) {
+				return sqliteDriverInstance.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"ALTER TABLE \"posts\" RENAME COLUMN \"name\" TO \"title\""},
+		},
+		{
+			name: "mysql rename column",
+			render: func() ([]string, errors.Error) {
+				return mysqlDriverInstance.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"ALTER TABLE `posts` RENAME COLUMN `name` TO `title`"},
+		},
+		{
+			name: "duckdb create table",
+			render: func() ([]string, errors.Error) {
+				table := schema.Table{
+					Name: "events",
+					Columns: []schema.Column{
+						{Name: "id", Type: "BIGINT", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}, schema.AutoIncrementConstraint{}}},
+						{Name: "tags", Type: "VARCHAR[]", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+					},
+				}
+				return duckdbDriverInstance.CreateTable(table)
+			},
+			want: []string{
+				"CREATE SEQUENCE \"events_id_seq\"",
+				"CREATE TABLE \"events\" (\n" +
+					"  \"id\" BIGINT DEFAULT nextval('events_id_seq') PRIMARY KEY,\n" +
+					"  \"tags\" VARCHAR[] NOT NULL\n" +
+					")",
+			},
+		},
+		{
+			name: "duckdb create table with referential action",
+			render: func() ([]string, errors.Error) {
+				return duckdbDriverInstance.CreateTable(ddlTestTable)
+			},
+			wantErr: true,
+		},
+		{
+			name: "duckdb add not null column",
+			render: func() ([]string, errors.Error) {
+				return duckdbDriverInstance.AddColumn(ddlTestTable, title)
+			},
+			want: []string{
+				"ALTER TABLE \"posts\" ADD COLUMN \"title\" VARCHAR(255) DEFAULT ''",
+				"ALTER TABLE \"posts\" ALTER COLUMN \"title\" SET NOT NULL",
+			},
+		},
+		{
+			name: "duckdb add unique",
+			render: func() ([]string, errors.Error) {
+				return duckdbDriverInstance.AddConstraint(ddlTestTable, title, schema.UniqueConstraint{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "duckdb drop auto increment",
+			render: func() ([]string, errors.Error) {
+				return duckdbDriverInstance.DropConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.AutoIncrementConstraint{})
+			},
+			want: []string{
+				"ALTER TABLE \"posts\" ALTER COLUMN \"id\" DROP DEFAULT",
+				"DROP SEQUENCE IF EXISTS \"posts_id_seq\"",
+			},
+		},
+		{
+			name: "duckdb create partial index",
+			render: func() ([]string, errors.Error) {
+				return duckdbDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Where: "title <> ''"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlserver alter type",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.AlterColumnType(ddlTestTable, title)
+			},
+			want: []string{"ALTER TABLE [posts] ALTER COLUMN [title] VARCHAR(255) NOT NULL"},
+		},
+		{
+			name: "sqlserver drop not null",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.DropConstraint(ddlTestTable, title, schema.NotNullConstraint{})
+			},
+			want: []string{"ALTER TABLE [posts] ALTER COLUMN [title] VARCHAR(255) NULL"},
+		},
+		{
+			name: "sqlserver add default",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.AddConstraint(ddlTestTable, title, schema.DefaultConstraint{Value: "'untitled'"})
+			},
+			want: []string{"ALTER TABLE [posts] ADD CONSTRAINT [posts_title_default] DEFAULT 'untitled' FOR [title]"},
+		},
+		{
+			name: "sqlserver add auto increment",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.AddConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.AutoIncrementConstraint{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlserver add column with foreign key",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.AddColumn(ddlTestTable, ddlTestTable.Columns[2])
+			},
+			want: []string{"ALTER TABLE [posts] ADD [user_id] INTEGER CONSTRAINT [posts_user_id_fkey] REFERENCES [users] ([id]) ON DELETE CASCADE"},
+		},
+		{
+			name: "sqlserver drop column with default",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.DropColumn(ddlTestTable, title)
+			},
+			want: []string{
+				"ALTER TABLE [posts] DROP CONSTRAINT [posts_title_default]",
+				"ALTER TABLE [posts] DROP COLUMN [title]",
+			},
+		},
+		{
+			name: "sqlserver rename table",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.RenameTable("articles", ddlTestTable)
+			},
+			want: []string{"EXEC sp_rename '[articles]', 'posts'"},
+		},
+		{
+			name: "sqlserver rename column",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"EXEC sp_rename '[posts].[name]', 'title', 'COLUMN'"},
+		},
+		{
+			name: "sqlserver create clustered filtered index",
+			render: func() ([]string, errors.Error) {
+				index := schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Unique: true, Method: "clustered", Where: "title <> ''"}
+				return sqlserverDriverInstance.CreateIndex(ddlTestTable, index)
+			},
+			want: []string{"CREATE UNIQUE CLUSTERED INDEX [posts_title_idx] ON [posts] ([title]) WHERE title <> ''"},
+		},
+		{
+			name: "sqlserver create index with method",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.CreateIndex(ddlTestTable, schema.Index{Name: "posts_title_idx", Columns: []string{"title"}, Method: "gin"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlserver drop index",
+			render: func() ([]string, errors.Error) {
+				return sqlserverDriverInstance.DropIndex(ddlTestTable, schema.Index{Name: "posts_title_idx"})
+			},
+			want: []string{"DROP INDEX [posts_title_idx] ON [posts]"},
+		},
+		{
+			name: "mariadb add column with check",
+			render: func() ([]string, errors.Error) {
+				column := schema.Column{Name: "rating", Type: "INT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.CheckConstraint{Expression: "rating > 0"}}}
+				return mariadbDriverInstance.AddColumn(ddlTestTable, column)
+			},
+			want: []string{
+				"ALTER TABLE `posts` ADD COLUMN `rating` INT NOT NULL",
+				"ALTER TABLE `posts` ADD CONSTRAINT `posts_rating_check` CHECK (rating > 0)",
+			},
+		},
+		{
+			name: "mariadb drop check",
+			render: func() ([]string, errors.Error) {
+				return mariadbDriverInstance.DropConstraint(ddlTestTable, title, schema.CheckConstraint{Expression: "title <> ''"})
+			},
+			want: []string{"ALTER TABLE `posts` DROP CONSTRAINT `posts_title_check`"},
+		},
+		{
+			name: "mariadb drop table check",
+			render: func() ([]string, errors.Error) {
+				return mariadbDriverInstance.DropTableConstraint(ddlTestTable, schema.TableConstraint{Name: "posts_check", Type: schema.CheckConstraintType})
+			},
+			want: []string{"ALTER TABLE `posts` DROP CONSTRAINT `posts_check`"},
+		},
+		{
+			name: "mariadb drop unique",
+			render: func() ([]string, errors.Error) {
+				return mariadbDriverInstance.DropConstraint(ddlTestTable, title, schema.UniqueConstraint{})
+			},
+			want: []string{"ALTER TABLE `posts` DROP INDEX `title`"},
+		},
+		{
+			name: "cockroachdb add primary key",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.AddConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.PrimaryKeyConstraint{})
+			},
+			want: []string{"ALTER TABLE \"posts\" ALTER PRIMARY KEY USING COLUMNS (\"id\")"},
+		},
+		{
+			name: "cockroachdb drop primary key",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.DropConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.PrimaryKeyConstraint{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "cockroachdb drop unique",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.DropConstraint(ddlTestTable, title, schema.UniqueConstraint{})
+			},
+			want: []string{"DROP INDEX \"posts\"@\"posts_title_key\" CASCADE"},
+		},
+		{
+			name: "cockroachdb drop table unique",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.DropTableConstraint(ddlTestTable, schema.TableConstraint{Name: "posts_title_user_id_key", Type: schema.UniqueConstraintType})
+			},
+			want: []string{"DROP INDEX \"posts\"@\"posts_title_user_id_key\" CASCADE"},
+		},
+		{
+			name: "cockroachdb drop check",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.DropConstraint(ddlTestTable, title, schema.CheckConstraint{Expression: "title <> ''"})
+			},
+			want: []string{"ALTER TABLE \"posts\" DROP CONSTRAINT \"posts_title_check\""},
+		},
+		{
+			name: "cockroachdb drop index",
+			render: func() ([]string, errors.Error) {
+				return cockroachdbDriverInstance.DropIndex(ddlTestTable, schema.Index{Name: "posts_title_idx"})
+			},
+			want: []string{"DROP INDEX \"posts\"@\"posts_title_idx\""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.render()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	if got := mysqlDriverInstance.QuoteIdentifier("we`ird"); got != "`we``ird`" {
+		t.Errorf("mysql QuoteIdentifier() = %s", got)
+	}
+	if got := postgresDriverInstance.QuoteIdentifier("we\"ird"); got != "\"we\"\"ird\"" {
+		t.Errorf("postgres QuoteIdentifier() = %s", got)
+	}
+	if got := sqlserverDriverInstance.QuoteIdentifier("we]ird"); got != "[we]]ird]" {
+		t.Errorf("sqlserver QuoteIdentifier() = %s", got)
+	}
+}
+
+func TestMariadbCreateTableWithChecks(t *testing.T) {
+	table := schema.Table{
+		Name: "products",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INT", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}, schema.AutoIncrementConstraint{}}},
+			{Name: "price", Type: "DECIMAL(10,2)", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.CheckConstraint{Expression: "price >= 0"}}},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "products_check", Type: schema.CheckConstraintType, Expression: "price < 1000"},
+		},
+	}
+	want := []string{"CREATE TABLE `products` (\n" +
+		"  `id` INT PRIMARY KEY AUTO_INCREMENT,\n" +
+		"  `price` DECIMAL(10,2) NOT NULL,\n" +
+		"  CONSTRAINT `products_price_check` CHECK (price >= 0),\n" +
+		"  CONSTRAINT `products_check` CHECK (price < 1000)\n" +
+		")"}
+	got, err := mariadbDriverInstance.CreateTable(table)
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateTable() = %q, want %q", got, want)
+	}
+	if len(table.Columns[1].Constraints) != 2 {
+		t.Errorf("CreateTable() modified the columns of the table")
+	}
+}
+
+// withVersion returns a copy of a mysql driver assuming another server
+// version.
+func withVersion(d *mysqlDriver, version Version) *mysqlDriver {
+	copied := *d
+	copied.version = version
+	return &copied
+}
+
+func TestVersionedStatements(t *testing.T) {
+	title := ddlTestTable.Columns[1]
+	mysql57 := withVersion(mysqlDriverInstance, Version{Major: 5, Minor: 7, Patch: 44})
+	mariadb101 := &mariadbDriver{mysqlDriver: withVersion(mariadbDriverInstance.mysqlDriver, Version{Major: 10, Minor: 1, Patch: 48})}
+	mariadb104 := &mariadbDriver{mysqlDriver: withVersion(mariadbDriverInstance.mysqlDriver, Version{Major: 10, Minor: 4, Patch: 0})}
+	sqlite334 := &sqliteDriver{version: Version{Major: 3, Minor: 34, Patch: 1}}
+	cockroachdb192 := &cockroachdbDriver{postgresDriver: &postgresDriver{version: Version{Major: 19, Minor: 2}}}
+	tests := []struct {
+		name    string
+		render  func() ([]string, errors.Error)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "mysql 5.7 add check",
+			render: func() ([]string, errors.Error) {
+				return mysql57.AddConstraint(ddlTestTable, title, schema.CheckConstraint{Expression: "title <> ''"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql 5.7 create table with check",
+			render: func() ([]string, errors.Error) {
+				table := schema.Table{Name: "posts", Columns: []schema.Column{{Name: "id", Type: "INT"}}, Constraints: []schema.TableConstraint{
+					{Name: "posts_check", Type: schema.CheckConstraintType, Expression: "id > 0"},
+				}}
+				return mysql57.CreateTable(table)
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql 5.7 rename column",
+			render: func() ([]string, errors.Error) {
+				return mysql57.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"ALTER TABLE `posts` CHANGE COLUMN `name` `title` VARCHAR(255) NOT NULL DEFAULT ''"},
+		},
+		{
+			name: "mariadb 10.4 rename column",
+			render: func() ([]string, errors.Error) {
+				return mariadb104.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"ALTER TABLE `posts` CHANGE COLUMN `name` `title` VARCHAR(255) NOT NULL DEFAULT ''"},
+		},
+		{
+			name: "mariadb 10.1 add check",
+			render: func() ([]string, errors.Error) {
+				return mariadb101.AddConstraint(ddlTestTable, title, schema.CheckConstraint{Expression: "title <> ''"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "mariadb 10.4 add check",
+			render: func() ([]string, errors.Error) {
+				return mariadb104.AddConstraint(ddlTestTable, title, schema.CheckConstraint{Expression: "title <> ''"})
+			},
+			want: []string{"ALTER TABLE `posts` ADD CONSTRAINT `posts_title_check` CHECK (title <> '')"},
+		},
+		{
+			name: "postgres add column with default",
+			render: func() ([]string, errors.Error) {
+				return postgresDriverInstance.AddColumn(ddlTestTable, title)
+			},
+			want: []string{"ALTER TABLE \"posts\" ADD COLUMN \"title\" VARCHAR(255) NOT NULL DEFAULT ''"},
+		},
+		{
+			name: "sqlite 3.34 drop column",
+			render: func() ([]string, errors.Error) {
+				return sqlite334.DropColumn(ddlTestTable, title)
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlite 3.34 rename column",
+			render: func() ([]string, errors.Error) {
+				return sqlite334.RenameColumn(ddlTestTable, "name", title)
+			},
+			want: []string{"ALTER TABLE \"posts\" RENAME COLUMN \"name\" TO \"title\""},
+		},
+		{
+			name: "cockroachdb 19.2 add primary key",
+			render: func() ([]string, errors.Error) {
+				return cockroachdb192.AddConstraint(ddlTestTable, ddlTestTable.Columns[0], schema.PrimaryKeyConstraint{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.render()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,16 +11,28 @@ import (
 type DriverType string
 
 const (
-	MysqlDriverType    DriverType = "mysql"
-	PostgresDriverType DriverType = "postgres"
-	SqliteDriverType   DriverType = "sqlite"
+	MysqlDriverType       DriverType = "mysql"
+	PostgresDriverType    DriverType = "postgres"
+	SqliteDriverType      DriverType = "sqlite"
+	SqlserverDriverType   DriverType = "sqlserver"
+	DuckdbDriverType      DriverType = "duckdb"
+	MariadbDriverType     DriverType = "mariadb"
+	CockroachdbDriverType DriverType = "cockroachdb"
 )
 
-var SupportedDrivers = []DriverType{
+// SupportedDrivers lists the drivers of the build, DuckDB is only part of
+// builds with the duckdb tag.
+var SupportedDrivers = slices.DeleteFunc([]DriverType{
 	MysqlDriverType,
 	PostgresDriverType,
 	SqliteDriverType,
-}
+	SqlserverDriverType,
+	DuckdbDriverType,
+	MariadbDriverType,
+	CockroachdbDriverType,
+}, func(driverType DriverType) bool {
+	return driverType == DuckdbDriverType && !duckdbEnabled
+})
 
 func GetDriver(driverType DriverType) Driver {
 	switch driverType {
@@ -30,34 +42,62 @@ func GetDriver(driverType DriverType) Driver {
 		return postgresDriverInstance
 	case SqliteDriverType:
 		return sqliteDriverInstance
+	case SqlserverDriverType:
+		return sqlserverDriverInstance
+	case DuckdbDriverType:
+		if !duckdbEnabled {
+			return nil
+		}
+		return duckdbDriverInstance
+	case MariadbDriverType:
+		return mariadbDriverInstance
+	case CockroachdbDriverType:
+		return cockroachdbDriverInstance
 	default:
 		return nil
 	}
 }
 
+// Result holds the rows returned by a query. It must be closed once read,
+// a connection cannot run another statement while its rows are open.
 type Result interface {
 	Next() bool
 	Scan(...any) error
+	Err() error
+	Close() error
 }
 
 type Driver interface {
+	Dialect
 	GetDataTypes() []schema.DataType
 	Connect(utils.DSN) errors.Error
 	Execute(string) errors.Error
 	Query(string) (Result, errors.Error)
+	Begin() errors.Error
+	Commit() errors.Error
+	Rollback() errors.Error
 	Close() errors.Error
-	Version() float32
+	Version() Version
 	GetTableNames() ([]string, errors.Error)
 	GetTable(string) (schema.Table, errors.Error)
 }
 
+// HasType reports whether the type is a type of the driver, possibly with
+// arguments and written with an alias, or a logical type the driver can map,
+// and is available in the version of the server.
 func HasType(d Driver, t schema.DataType) bool {
-	driverTypes := d.GetDataTypes()
-	tIndex := slices.IndexFunc(
-		driverTypes,
-		func(s schema.DataType) bool {
-			return s.Equals(t)
-		},
-	)
-	return tIndex != -1
+	if _, _, ok := t.Logical(); ok {
+		resolved, err := d.ResolveType(t)
+		if err != nil {
+			return false
+		}
+		t = resolved
+	}
+	parsed, ok := d.NormalizeType(t)
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(d.GetDataTypes(), func(s schema.DataType) bool {
+		return s.Equals(schema.DataType(parsed.Base))
+	})
 }
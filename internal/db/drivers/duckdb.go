@@ -0,0 +1,348 @@
+package drivers
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+	"github.com/yassirdeveloper/migrater/internal/utils"
+)
+
+// duckdbDriver manages DuckDB database files. The duckdb database/sql driver
+// links DuckDB through cgo and is only registered in builds with the duckdb
+// tag, see duckdb_cgo.go. GetDriver does not return it in other builds.
+type duckdbDriver struct {
+	version      Version
+	dataTypes    []schema.DataType
+	typeVersions map[schema.DataType]Version
+	db           *sql.DB
+	tx           *sql.Tx
+}
+
+func (d *duckdbDriver) GetDataTypes() []schema.DataType {
+	return availableTypes(d.dataTypes, d.typeVersions, d.version)
+}
+
+func (d *duckdbDriver) Connect(dsn utils.DSN) errors.Error {
+	if d.db != nil {
+		err := d.db.Close()
+		if err != nil {
+			return errors.NewUnexpectedError(err)
+		}
+	}
+	db, err := sql.Open("duckdb", dsn.String())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Could not establish connection!\n%s", err))
+	}
+	if err := db.Ping(); err != nil {
+		return errors.New(fmt.Sprintf("Could not ping database!\n%s", err))
+	}
+	version, err_ := serverVersion(db, "SELECT version()")
+	if err_ != nil {
+		db.Close()
+		return err_
+	}
+	d.db = db
+	d.version = version
+	return nil
+}
+
+func (d *duckdbDriver) Execute(query string) errors.Error {
+	var err error
+	if d.tx != nil {
+		_, err = d.tx.Exec(query)
+	} else {
+		_, err = d.db.Exec(query)
+	}
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *duckdbDriver) Query(query string) (Result, errors.Error) {
+	var rows *sql.Rows
+	var err error
+	if d.tx != nil {
+		rows, err = d.tx.Query(query)
+	} else {
+		rows, err = d.db.Query(query)
+	}
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return rows, nil
+}
+
+func (d *duckdbDriver) Begin() errors.Error {
+	if d.tx != nil {
+		return errors.New("transaction already started")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	d.tx = tx
+	return nil
+}
+
+func (d *duckdbDriver) Commit() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to commit")
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *duckdbDriver) Rollback() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to rollback")
+	}
+	err := d.tx.Rollback()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *duckdbDriver) GetTableNames() ([]string, errors.Error) {
+	query := `SELECT table_name FROM information_schema.tables
+	WHERE table_catalog = current_database() AND table_schema = current_schema() AND table_type = 'BASE TABLE'
+	ORDER BY table_name`
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+
+	return tableNames, nil
+}
+
+func (d *duckdbDriver) GetTable(tableName string) (schema.Table, errors.Error) {
+	columns, err := d.getColumns(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	constraints, err := d.getConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	indexes, err := d.getIndexes(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	columnConstraints, tableConstraints := splitConstraints(d, tableName, constraints)
+	for i := range columns {
+		columns[i].Constraints = append(columns[i].Constraints, columnConstraints[columns[i].Name]...)
+	}
+	return schema.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Constraints: tableConstraints,
+		Indexes:     indexes,
+	}, nil
+}
+
+var duckdbSequenceDefault = regexp.MustCompile(`^nextval\('[^']+'\)$`)
+
+// getColumns returns the columns of a table with their NOT NULL and DEFAULT
+// constraints. A default taken from a sequence stands for auto increment.
+func (d *duckdbDriver) getColumns(tableName string) ([]schema.Column, errors.Error) {
+	query := `SELECT column_name, data_type, is_nullable, column_default
+	FROM duckdb_columns()
+	WHERE database_name = current_database() AND schema_name = current_schema() AND table_name = ?
+	ORDER BY column_index`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var columns []schema.Column
+	for rows.Next() {
+		var column schema.Column
+		var dataType string
+		var isNullable bool
+		var columnDefault sql.NullString
+		if err := rows.Scan(&column.Name, &dataType, &isNullable, &columnDefault); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		column.Type = schema.DataType(dataType)
+		if !isNullable {
+			column.Constraints = append(column.Constraints, schema.NotNullConstraint{})
+		}
+		if columnDefault.Valid {
+			if duckdbSequenceDefault.MatchString(columnDefault.String) {
+				column.Constraints = append(column.Constraints, schema.AutoIncrementConstraint{})
+			} else {
+				column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: columnDefault.String})
+			}
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return columns, nil
+}
+
+// getConstraints returns the key and check constraints of a table. DuckDB
+// does not keep the names of constraints and reports them under the names
+// of the constraint naming convention.
+func (d *duckdbDriver) getConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT constraint_name, constraint_type, array_to_string(constraint_column_names, ','),
+		COALESCE(referenced_table, ''), COALESCE(array_to_string(referenced_column_names, ','), ''),
+		COALESCE(expression, '')
+	FROM duckdb_constraints()
+	WHERE database_name = current_database() AND schema_name = current_schema() AND table_name = ?
+		AND constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY', 'CHECK')
+	ORDER BY constraint_index`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, constraintType, columns, referencedTable, referencedColumns, expression string
+		if err := rows.Scan(&name, &constraintType, &columns, &referencedTable, &referencedColumns, &expression); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraint := schema.TableConstraint{
+			Name:    name,
+			Type:    keyConstraintType(constraintType),
+			Columns: duckdbList(columns),
+		}
+		switch constraintType {
+		case "FOREIGN KEY":
+			constraint.ReferencedTable = referencedTable
+			constraint.ReferencedColumns = duckdbList(referencedColumns)
+		case "CHECK":
+			constraint.Type = schema.CheckConstraintType
+			constraint.Expression = checkExpression(expression)
+		}
+		constraints = append(constraints, constraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// duckdbList splits a list converted with array_to_string.
+func duckdbList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+var duckdbIndexColumns = regexp.MustCompile(`(?is)\bON\s+\S+?\s*\((.*)\)\s*;?\s*$`)
+
+// getIndexes returns the indexes created on a table, DuckDB only reports
+// their columns in the statement that created them.
+func (d *duckdbDriver) getIndexes(tableName string) ([]schema.Index, errors.Error) {
+	query := `SELECT index_name, is_unique, sql
+	FROM duckdb_indexes()
+	WHERE database_name = current_database() AND schema_name = current_schema() AND table_name = ?
+	ORDER BY index_name`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var indexes []schema.Index
+	for rows.Next() {
+		var index schema.Index
+		var createSQL string
+		if err := rows.Scan(&index.Name, &index.Unique, &createSQL); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if match := duckdbIndexColumns.FindStringSubmatch(createSQL); match != nil {
+			for _, column := range strings.Split(match[1], ",") {
+				index.Columns = append(index.Columns, strings.Trim(strings.TrimSpace(column), `"`))
+			}
+		}
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return indexes, nil
+}
+
+func (d *duckdbDriver) Version() Version {
+	return d.version
+}
+
+func (d *duckdbDriver) Close() errors.Error {
+	if d.db != nil {
+		err := d.db.Close()
+		if err != nil {
+			return errors.NewUnexpectedError(err)
+		}
+		return nil
+	}
+	return nil
+}
+
+var duckdbDriverInstance = &duckdbDriver{
+	version: Version{Major: 1, Minor: 1, Patch: 0},
+	dataTypes: []schema.DataType{
+		"BOOLEAN",
+		"TINYINT",
+		"SMALLINT",
+		"INTEGER",
+		"BIGINT",
+		"HUGEINT",
+		"UTINYINT",
+		"USMALLINT",
+		"UINTEGER",
+		"UBIGINT",
+		"UHUGEINT",
+		"FLOAT",
+		"DOUBLE",
+		"DECIMAL",
+		"VARCHAR",
+		"BLOB",
+		"BIT",
+		"UUID",
+		"JSON",
+		"DATE",
+		"TIME",
+		"TIMETZ",
+		"TIMESTAMP",
+		"TIMESTAMPTZ",
+		"TIMESTAMP_S",
+		"TIMESTAMP_MS",
+		"TIMESTAMP_NS",
+		"INTERVAL",
+		"ENUM",
+		"LIST",
+		"STRUCT",
+		"MAP",
+		"UNION",
+	},
+}
@@ -0,0 +1,9 @@
+//go:build duckdb
+
+package drivers
+
+import _ "github.com/marcboeker/go-duckdb"
+
+// duckdbEnabled reports whether the duckdb database/sql driver is linked in,
+// DuckDB is only supported when it is.
+const duckdbEnabled = true
@@ -0,0 +1,65 @@
+//go:build duckdb
+
+package drivers
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+	"github.com/yassirdeveloper/migrater/internal/utils"
+)
+
+func TestDuckdbFile(t *testing.T) {
+	dsn, err := utils.ToDSN("file:"+filepath.Join(t.TempDir(), "test.duckdb"), utils.DSNFormatDuckDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &duckdbDriver{dataTypes: duckdbDriverInstance.dataTypes, typeVersions: duckdbDriverInstance.typeVersions}
+	if err := d.Connect(*dsn); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	events := schema.Table{
+		Name: "events",
+		Columns: []schema.Column{
+			{Name: "id", Type: "BIGINT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.AutoIncrementConstraint{}, schema.PrimaryKeyConstraint{}}},
+			{Name: "kind", Type: "VARCHAR", Constraints: schema.Constraints{
+				schema.NotNullConstraint{},
+				schema.DefaultConstraint{Value: "'click'"},
+				schema.CheckConstraint{Expression: "kind <> ''"},
+			}},
+		},
+		Indexes: []schema.Index{{Name: "events_kind_idx", Columns: []string{"kind"}}},
+	}
+	statements, err := d.CreateTable(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := d.CreateIndex(events, events.Indexes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, statement := range append(statements, index...) {
+		if err := d.Execute(statement); err != nil {
+			t.Fatalf("%s: %s", statement, err.Display())
+		}
+	}
+
+	names, err := d.GetTableNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"events"}) {
+		t.Errorf("GetTableNames() = %q, want [events]", names)
+	}
+	got, err := d.GetTable("events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("GetTable() = %+v, want %+v", got, events)
+	}
+}
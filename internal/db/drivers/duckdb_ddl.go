@@ -0,0 +1,327 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func (d *duckdbDriver) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (d *duckdbDriver) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "\"")
+}
+
+func (d *duckdbDriver) QuoteLiteral(value string) string {
+	return quoteLiteral(value)
+}
+
+func (d *duckdbDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return duckdbTypeRules.resolveType(t)
+}
+
+func (d *duckdbDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return duckdbTypeRules.normalizeType(t)
+}
+
+// AutoIncrementClause is empty, auto increment columns default to the next
+// value of their sequence instead.
+func (d *duckdbDriver) AutoIncrementClause() string {
+	return ""
+}
+
+func (d *duckdbDriver) PrimaryKeyClause(column schema.Column) string {
+	return " PRIMARY KEY"
+}
+
+func (d *duckdbDriver) UniqueConstraintName(table schema.Table, column schema.Column) string {
+	return constraintName(table, column, "key")
+}
+
+func (d *duckdbDriver) LargeTextType() schema.DataType {
+	return "VARCHAR"
+}
+
+// sequenceName returns the sequence backing an auto increment column, which
+// DuckDB only supports as a default taken from a sequence.
+func (d *duckdbDriver) sequenceName(table schema.Table, column schema.Column) string {
+	return constraintName(table, column, "seq")
+}
+
+func (d *duckdbDriver) columnDefinition(table schema.Table, column schema.Column) string {
+	definition := fmt.Sprintf("%s %s", d.QuoteIdentifier(column.Name), column.Type)
+	if schema.HasConstraint(column.Constraints, schema.AutoIncrementConstraint{}) {
+		definition += fmt.Sprintf(" DEFAULT nextval(%s)", d.QuoteLiteral(d.sequenceName(table, column)))
+	}
+	return definition + inlineConstraints(d, table, column, true)
+}
+
+// checkReferentialActions refuses the foreign keys of a table with an
+// ON DELETE or ON UPDATE action, DuckDB only supports the default one.
+func (d *duckdbDriver) checkReferentialActions(table schema.Table) errors.Error {
+	for _, column := range table.Columns {
+		for _, constraint := range column.Constraints {
+			if fk, ok := constraint.(schema.ForeignKeyConstraint); ok && (fk.OnDelete != "" || fk.OnUpdate != "") {
+				return errors.New(fmt.Sprintf("duckdb does not support referential actions, foreign key of column %s.%s has one", table.Name, column.Name))
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.OnDelete != "" || constraint.OnUpdate != "" {
+			return errors.New(fmt.Sprintf("duckdb does not support referential actions, foreign key %s of table %s has one", constraint.Name, table.Name))
+		}
+	}
+	return nil
+}
+
+// CreateTable creates the sequences of the auto increment columns before
+// the table.
+func (d *duckdbDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	if err := d.checkReferentialActions(table); err != nil {
+		return nil, err
+	}
+	var statements []string
+	for _, column := range table.Columns {
+		if schema.HasConstraint(column.Constraints, schema.AutoIncrementConstraint{}) {
+			statements = append(statements, fmt.Sprintf("CREATE SEQUENCE %s", d.QuoteIdentifier(d.sequenceName(table, column))))
+		}
+	}
+	return append(statements, createTable(d, table, d.columnDefinition)), nil
+}
+
+func (d *duckdbDriver) DropTable(table schema.Table) ([]string, errors.Error) {
+	statements := []string{fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name))}
+	for _, column := range table.Columns {
+		if schema.HasConstraint(column.Constraints, schema.AutoIncrementConstraint{}) {
+			statements = append(statements, fmt.Sprintf("DROP SEQUENCE IF EXISTS %s", d.QuoteIdentifier(d.sequenceName(table, column))))
+		}
+	}
+	return statements, nil
+}
+
+func (d *duckdbDriver) RenameTable(previousName string, table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", d.QuoteIdentifier(previousName), d.QuoteIdentifier(table.Name))}, nil
+}
+
+// AddColumn adds the column with its default, DuckDB does not support
+// other constraints in ADD COLUMN and NOT NULL is set afterwards.
+func (d *duckdbDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	definition := fmt.Sprintf("%s %s", d.QuoteIdentifier(column.Name), column.Type)
+	var statements []string
+	notNull := false
+	for _, constraint := range column.GetConstraints() {
+		switch c := constraint.(type) {
+		case schema.DefaultConstraint:
+			definition += " DEFAULT " + c.Value
+		case schema.AutoIncrementConstraint:
+			statements = append(statements, fmt.Sprintf("CREATE SEQUENCE %s", d.QuoteIdentifier(d.sequenceName(table, column))))
+			definition += fmt.Sprintf(" DEFAULT nextval(%s)", d.QuoteLiteral(d.sequenceName(table, column)))
+		case schema.NotNullConstraint:
+			notNull = true
+		default:
+			return nil, errors.New(fmt.Sprintf("duckdb cannot add column %s.%s with constraint %s", table.Name, column.Name, c.Name()))
+		}
+	}
+	statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName, definition))
+	if notNull {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", tableName, d.QuoteIdentifier(column.Name)))
+	}
+	return statements, nil
+}
+
+func (d *duckdbDriver) DropColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	statements := []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(column.Name))}
+	if schema.HasConstraint(column.Constraints, schema.AutoIncrementConstraint{}) {
+		statements = append(statements, fmt.Sprintf("DROP SEQUENCE IF EXISTS %s", d.QuoteIdentifier(d.sequenceName(table, column))))
+	}
+	return statements, nil
+}
+
+func (d *duckdbDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	return []string{renameColumn(d, table, previousName, column)}, nil
+}
+
+func (d *duckdbDriver) AlterColumnType(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{fmt.Sprintf(
+		"ALTER TABLE %s ALTER COLUMN %s TYPE %s",
+		d.QuoteIdentifier(table.Name),
+		d.QuoteIdentifier(column.Name),
+		column.Type,
+	)}, nil
+}
+
+// AddConstraint supports the constraints DuckDB can alter on an existing
+// column: NOT NULL, defaults and primary keys.
+func (d *duckdbDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	switch c := constraint.(type) {
+	case schema.NotNullConstraint:
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", tableName, columnName)}, nil
+	case schema.DefaultConstraint:
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", tableName, columnName, c.Value)}, nil
+	case schema.AutoIncrementConstraint:
+		sequence := d.sequenceName(table, column)
+		return []string{
+			fmt.Sprintf("CREATE SEQUENCE %s", d.QuoteIdentifier(sequence)),
+			fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval(%s)", tableName, columnName, d.QuoteLiteral(sequence)),
+		}, nil
+	case schema.PrimaryKeyConstraint:
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", tableName, columnName)}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("duckdb cannot add constraint %s to existing column %s.%s", constraint.Name(), table.Name, column.Name))
+}
+
+func (d *duckdbDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	switch constraint.(type) {
+	case schema.NotNullConstraint:
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", tableName, columnName)}, nil
+	case schema.DefaultConstraint:
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName)}, nil
+	case schema.AutoIncrementConstraint:
+		return []string{
+			fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName),
+			fmt.Sprintf("DROP SEQUENCE IF EXISTS %s", d.QuoteIdentifier(d.sequenceName(table, column))),
+		}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("duckdb cannot drop constraint %s from column %s.%s", constraint.Name(), table.Name, column.Name))
+}
+
+func (d *duckdbDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	if constraint.Type == schema.PrimaryKeyConstraintType {
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", d.QuoteIdentifier(table.Name), quoteIdentifiers(d, constraint.Columns))}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("duckdb cannot add constraint %s to existing table %s", constraint.Name, table.Name))
+}
+
+func (d *duckdbDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return nil, errors.New(fmt.Sprintf("duckdb cannot drop constraint %s from table %s", constraint.Name, table.Name))
+}
+
+// CreateIndex only supports the default ART index, without a where clause.
+func (d *duckdbDriver) CreateIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	if index.Method != "" && !strings.EqualFold(index.Method, "art") {
+		return nil, errors.New(fmt.Sprintf("duckdb does not support index methods, index %s uses %s", index.Name, index.Method))
+	}
+	if index.Where != "" {
+		return nil, errors.New(fmt.Sprintf("duckdb does not support partial indexes, index %s has a where clause", index.Name))
+	}
+	return []string{createIndex(d, table, index, "")}, nil
+}
+
+func (d *duckdbDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP INDEX %s", d.QuoteIdentifier(index.Name))}, nil
+}
+
+func (d *duckdbDriver) IndexNamesPerTable() bool {
+	return false
+}
+
+func (d *duckdbDriver) UniqueIndexesAsConstraints() bool {
+	return false
+}
+
+// duckdbLogicalTypes maps logical types to DuckDB types. DuckDB ignores the
+// length of VARCHAR, which is normalized away.
+var duckdbLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INTEGER"},
+	schema.Int64Type:     {plain: "BIGINT"},
+	schema.StringType:    {plain: "VARCHAR", parameterized: "VARCHAR(%s)"},
+	schema.DecimalType:   {plain: "DECIMAL", parameterized: "DECIMAL(%s)"},
+	schema.BoolType:      {plain: "BOOLEAN"},
+	schema.TimestampType: {plain: "TIMESTAMP"},
+	schema.UUIDType:      {plain: "UUID"},
+	schema.JSONType:      {plain: "JSON"},
+	schema.BytesType:     {plain: "BLOB"},
+}
+
+var duckdbAliases = map[string]schema.DataType{
+	"INT":                      "INTEGER",
+	"INT4":                     "INTEGER",
+	"INT32":                    "INTEGER",
+	"SIGNED":                   "INTEGER",
+	"INT8":                     "BIGINT",
+	"INT64":                    "BIGINT",
+	"LONG":                     "BIGINT",
+	"INT2":                     "SMALLINT",
+	"INT16":                    "SMALLINT",
+	"SHORT":                    "SMALLINT",
+	"INT1":                     "TINYINT",
+	"BOOL":                     "BOOLEAN",
+	"LOGICAL":                  "BOOLEAN",
+	"FLOAT4":                   "FLOAT",
+	"REAL":                     "FLOAT",
+	"FLOAT8":                   "DOUBLE",
+	"DOUBLE PRECISION":         "DOUBLE",
+	"NUMERIC":                  "DECIMAL",
+	"STRING":                   "VARCHAR",
+	"TEXT":                     "VARCHAR",
+	"CHAR":                     "VARCHAR",
+	"BPCHAR":                   "VARCHAR",
+	"CHARACTER VARYING":        "VARCHAR",
+	"BYTEA":                    "BLOB",
+	"BINARY":                   "BLOB",
+	"VARBINARY":                "BLOB",
+	"BITSTRING":                "BIT",
+	"DATETIME":                 "TIMESTAMP",
+	"TIMESTAMP WITH TIME ZONE": "TIMESTAMPTZ",
+	"TIME WITH TIME ZONE":      "TIMETZ",
+}
+
+var duckdbTypeRules = typeRules{
+	logical:   duckdbLogicalTypes,
+	aliases:   duckdbAliases,
+	arrays:    true,
+	normalize: duckdbNormalize,
+}
+
+// duckdbNormalize writes types as DuckDB reports them: LIST(t) as t[],
+// VARCHAR without a length, DECIMAL with its default precision and scale of
+// 18 and 3, and the types nested in STRUCT, UNION and MAP normalized.
+func duckdbNormalize(parsed schema.ParsedType) schema.ParsedType {
+	switch parsed.Base {
+	case "LIST":
+		if len(parsed.Arguments) == 1 {
+			element, _ := duckdbNestedRules().normalizeType(schema.DataType(parsed.Arguments[0]))
+			element.ArrayDimensions += parsed.ArrayDimensions + 1
+			return element
+		}
+	case "VARCHAR":
+		parsed.Arguments = nil
+	case "DECIMAL":
+		if len(parsed.Arguments) == 0 {
+			parsed.Arguments = []string{"18", "3"}
+		} else if len(parsed.Arguments) == 1 {
+			parsed.Arguments = []string{parsed.Arguments[0], "0"}
+		}
+	case "STRUCT", "UNION":
+		fields := make([]string, 0, len(parsed.Arguments))
+		for _, field := range parsed.Arguments {
+			name, fieldType, _ := strings.Cut(field, " ")
+			normalized, _ := duckdbNestedRules().normalizeType(schema.DataType(fieldType))
+			fields = append(fields, name+" "+normalized.String())
+		}
+		parsed.Arguments = fields
+	case "MAP":
+		types := make([]string, 0, len(parsed.Arguments))
+		for _, argument := range parsed.Arguments {
+			normalized, _ := duckdbNestedRules().normalizeType(schema.DataType(argument))
+			types = append(types, normalized.String())
+		}
+		parsed.Arguments = types
+	}
+	return parsed
+}
+
+// duckdbNestedRules returns the rules of the types nested in other types,
+// which are duckdbTypeRules without its logical types.
+func duckdbNestedRules() typeRules {
+	return typeRules{aliases: duckdbAliases, arrays: true, normalize: duckdbNormalize}
+}
@@ -0,0 +1,5 @@
+//go:build !duckdb
+
+package drivers
+
+const duckdbEnabled = false
@@ -0,0 +1,60 @@
+package drivers
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestDuckdbGetTable(t *testing.T) {
+	db := sql.OpenDB(fakeDatabase{
+		"FROM duckdb_columns()": {
+			{"id", "BIGINT", false, "nextval('events_id_seq')"},
+			{"user_id", "INTEGER", true, nil},
+			{"kind", "VARCHAR", false, "'click'"},
+			{"tags", "VARCHAR[]", true, nil},
+			{"payload", "STRUCT(a INTEGER, b VARCHAR)", true, nil},
+		},
+		"FROM duckdb_constraints()": {
+			{"events_id_pkey", "PRIMARY KEY", "id", "", "", ""},
+			{"events_user_id_fkey", "FOREIGN KEY", "user_id", "users", "id", ""},
+			{"events_kind_check", "CHECK", "kind", "", "", "(kind <> '')"},
+			{"events_user_id_kind_key", "UNIQUE", "user_id,kind", "", "", ""},
+		},
+		"FROM duckdb_indexes()": {
+			{"events_kind_idx", false, `CREATE INDEX events_kind_idx ON events(kind, "user_id");`},
+		},
+	})
+	defer db.Close()
+	d := &duckdbDriver{db: db}
+
+	expected := schema.Table{
+		Name: "events",
+		Columns: []schema.Column{
+			{Name: "id", Type: "BIGINT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.AutoIncrementConstraint{}, schema.PrimaryKeyConstraint{}}},
+			{Name: "user_id", Type: "INTEGER", Constraints: schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id"}}},
+			{Name: "kind", Type: "VARCHAR", Constraints: schema.Constraints{
+				schema.NotNullConstraint{},
+				schema.DefaultConstraint{Value: "'click'"},
+				schema.CheckConstraint{Expression: "kind <> ''"},
+			}},
+			{Name: "tags", Type: "VARCHAR[]"},
+			{Name: "payload", Type: "STRUCT(a INTEGER, b VARCHAR)"},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "events_user_id_kind_key", Type: schema.UniqueConstraintType, Columns: []string{"user_id", "kind"}},
+		},
+		Indexes: []schema.Index{
+			{Name: "events_kind_idx", Columns: []string{"kind", "user_id"}},
+		},
+	}
+	table, err := d.GetTable("events")
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Errorf("GetTable() = %+v, want %+v", table, expected)
+	}
+}
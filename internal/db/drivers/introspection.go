@@ -0,0 +1,135 @@
+package drivers
+
+import (
+	"strings"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// isColumnConstraint reports whether a constraint read from a database is
+// declared on its column rather than on the table: primary keys and unnamed
+// constraints of a single column, and the ones named after the constraint
+// naming convention.
+func isColumnConstraint(d Dialect, tableName string, constraint schema.TableConstraint) bool {
+	if len(constraint.Columns) != 1 {
+		return false
+	}
+	if constraint.Type == schema.PrimaryKeyConstraintType || constraint.Name == "" {
+		return true
+	}
+	table := schema.Table{Name: tableName}
+	column := schema.Column{Name: constraint.Columns[0]}
+	switch constraint.Type {
+	case schema.UniqueConstraintType:
+		return constraint.Name == d.UniqueConstraintName(table, column)
+	case schema.ForeignKeyConstraintType:
+		return constraint.Name == constraintName(table, column, "fkey")
+	case schema.CheckConstraintType:
+		return constraint.Name == constraintName(table, column, "check")
+	default:
+		return false
+	}
+}
+
+// splitConstraints separates the constraints read from a database into the
+// constraints of each column, by column name, and the constraints of the
+// table. The columns of table level checks are dropped since they are not
+// declared in schemas.
+func splitConstraints(d Dialect, tableName string, constraints []schema.TableConstraint) (map[string][]schema.Constraint, []schema.TableConstraint) {
+	columnConstraints := make(map[string][]schema.Constraint)
+	var tableConstraints []schema.TableConstraint
+	for _, constraint := range constraints {
+		if !isColumnConstraint(d, tableName, constraint) {
+			if constraint.Type == schema.CheckConstraintType {
+				constraint.Columns = nil
+			}
+			tableConstraints = append(tableConstraints, constraint)
+			continue
+		}
+		column := constraint.Columns[0]
+		switch constraint.Type {
+		case schema.PrimaryKeyConstraintType:
+			columnConstraints[column] = append(columnConstraints[column], schema.PrimaryKeyConstraint{})
+		case schema.UniqueConstraintType:
+			columnConstraints[column] = append(columnConstraints[column], schema.UniqueConstraint{})
+		case schema.ForeignKeyConstraintType:
+			columnConstraints[column] = append(columnConstraints[column], schema.ForeignKeyConstraint{
+				ReferencedTable:  constraint.ReferencedTable,
+				ReferencedColumn: constraint.ReferencedColumns[0],
+				OnDelete:         constraint.OnDelete,
+				OnUpdate:         constraint.OnUpdate,
+			})
+		case schema.CheckConstraintType:
+			columnConstraints[column] = append(columnConstraints[column], schema.CheckConstraint{Expression: constraint.Expression})
+		}
+	}
+	return columnConstraints, tableConstraints
+}
+
+// appendKeyColumn adds a column of a key constraint read one row per column,
+// starting a new constraint when its name changes.
+func appendKeyColumn(constraints []schema.TableConstraint, constraint schema.TableConstraint, column string, referencedColumn string) []schema.TableConstraint {
+	if len(constraints) == 0 || constraints[len(constraints)-1].Name != constraint.Name {
+		constraints = append(constraints, constraint)
+	}
+	last := &constraints[len(constraints)-1]
+	last.Columns = append(last.Columns, column)
+	if last.Type == schema.ForeignKeyConstraintType {
+		last.ReferencedColumns = append(last.ReferencedColumns, referencedColumn)
+	}
+	return constraints
+}
+
+// keyConstraintType converts an information_schema constraint type.
+func keyConstraintType(constraintType string) string {
+	switch constraintType {
+	case "PRIMARY KEY":
+		return schema.PrimaryKeyConstraintType
+	case "UNIQUE":
+		return schema.UniqueConstraintType
+	case "FOREIGN KEY":
+		return schema.ForeignKeyConstraintType
+	default:
+		return constraintType
+	}
+}
+
+// checkExpression extracts the expression of a CHECK definition without its
+// surrounding parentheses.
+func checkExpression(definition string) string {
+	return unwrapParentheses(strings.TrimPrefix(definition, "CHECK"))
+}
+
+// unwrapParentheses removes the parentheses surrounding a whole expression.
+func unwrapParentheses(expression string) string {
+	expression = strings.TrimSpace(expression)
+	for strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") && balanced(expression[1:len(expression)-1]) {
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+	return expression
+}
+
+func balanced(expression string) bool {
+	depth := 0
+	for _, r := range expression {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// referentialAction returns the ON DELETE/ON UPDATE action of a foreign
+// key, NO ACTION being the default is returned as empty.
+func referentialAction(rule string) string {
+	if rule == "NO ACTION" {
+		return ""
+	}
+	return rule
+}
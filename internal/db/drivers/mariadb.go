@@ -0,0 +1,83 @@
+package drivers
+
+import (
+	"slices"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// mariadbDriver manages MariaDB servers. It reuses the connection and most
+// of the introspection and DDL of the mysql driver, and overrides the parts
+// where MariaDB differs: the reported defaults, checks and JSON columns.
+type mariadbDriver struct {
+	*mysqlDriver
+}
+
+func (d *mariadbDriver) GetTable(tableName string) (schema.Table, errors.Error) {
+	return d.getTable(tableName, mariadbColumnDefault, d.getCheckConstraints)
+}
+
+// mariadbColumnDefault returns a default value as it is written in a column
+// definition. mariadb reports defaults as expressions, literals already
+// being quoted, and a DEFAULT NULL as NULL.
+func mariadbColumnDefault(dataType string, value string, extra string) string {
+	return value
+}
+
+// getCheckConstraints returns the check constraints of a table. mariadb
+// records the table of a check, and adds a json_valid check named after the
+// column to every JSON column, which stands for the JSON type and is left
+// out.
+func (d *mariadbDriver) getCheckConstraints(tableName string, columns []schema.Column) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT CONSTRAINT_NAME, CHECK_CLAUSE
+	FROM information_schema.CHECK_CONSTRAINTS
+	WHERE CONSTRAINT_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	ORDER BY CONSTRAINT_NAME`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	table := schema.Table{Name: tableName}
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, clause string
+		if err := rows.Scan(&name, &clause); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		expression := mysqlCheckExpression(clause)
+		isJSON := slices.ContainsFunc(columns, func(column schema.Column) bool {
+			return name == column.Name && expression == "json_valid("+column.Name+")"
+		})
+		if isJSON {
+			continue
+		}
+		constraint := schema.TableConstraint{Name: name, Type: schema.CheckConstraintType, Expression: expression}
+		for _, column := range columns {
+			if name == constraintName(table, column, "check") {
+				constraint.Columns = []string{column.Name}
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+var mariadbDriverInstance = &mariadbDriver{
+	mysqlDriver: &mysqlDriver{
+		name:        "mariadb",
+		version:     Version{Major: 10, Minor: 11, Patch: 0},
+		checksSince: Version{Major: 10, Minor: 2, Patch: 1},
+		dataTypes:   slices.Concat(mysqlDataTypes, []schema.DataType{"INET4", "INET6", "UUID"}),
+		typeVersions: map[schema.DataType]Version{
+			"INET6": {Major: 10, Minor: 5, Patch: 0},
+			"UUID":  mariadbUUIDSince,
+			"INET4": {Major: 10, Minor: 10, Patch: 0},
+		},
+	},
+}
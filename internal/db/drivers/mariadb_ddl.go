@@ -0,0 +1,139 @@
+package drivers
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// mariadbUUIDSince is the version introducing the UUID type.
+var mariadbUUIDSince = Version{Major: 10, Minor: 7, Patch: 0}
+
+// ResolveType maps uuid to CHAR(36) as mysql does on servers without the
+// UUID type.
+func (d *mariadbDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	if logical, _, ok := t.Logical(); ok && logical == schema.UUIDType && !d.version.AtLeast(mariadbUUIDSince) {
+		return mysqlTypeRules.resolveType(t)
+	}
+	return mariadbTypeRules.resolveType(t)
+}
+
+func (d *mariadbDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return mariadbTypeRules.normalizeType(t)
+}
+
+// CreateTable declares the checks of the columns as table constraints,
+// mariadb does not accept a constraint name in a column definition and
+// would name them after their column.
+func (d *mariadbDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	return d.mysqlDriver.CreateTable(mariadbTableChecks(table))
+}
+
+func (d *mariadbDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	checks := make([]schema.Constraint, 0)
+	constraints := make(schema.Constraints, 0, len(column.Constraints))
+	for _, constraint := range column.Constraints {
+		if _, ok := constraint.(schema.CheckConstraint); ok {
+			checks = append(checks, constraint)
+		} else {
+			constraints = append(constraints, constraint)
+		}
+	}
+	column.Constraints = constraints
+	statements, err := d.mysqlDriver.AddColumn(table, column)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range checks {
+		sql, err := d.AddConstraint(table, column, check)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, sql...)
+	}
+	return statements, nil
+}
+
+// mariadbRenameColumnSince is the version introducing RENAME COLUMN.
+var mariadbRenameColumnSince = Version{Major: 10, Minor: 5, Patch: 2}
+
+// RenameColumn restates the column with CHANGE COLUMN on servers older than
+// 10.5.2, which have no RENAME COLUMN.
+func (d *mariadbDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	if !d.version.AtLeast(mariadbRenameColumnSince) {
+		return []string{d.changeColumn(table, previousName, column)}, nil
+	}
+	return []string{renameColumn(d, table, previousName, column)}, nil
+}
+
+// DropConstraint drops checks with DROP CONSTRAINT, mariadb has no DROP
+// CHECK.
+func (d *mariadbDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	if _, ok := constraint.(schema.CheckConstraint); ok {
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(constraintName(table, column, "check")))}, nil
+	}
+	return d.mysqlDriver.DropConstraint(table, column, constraint)
+}
+
+func (d *mariadbDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	if constraint.Type == schema.CheckConstraintType {
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(constraint.Name))}, nil
+	}
+	return d.mysqlDriver.DropTableConstraint(table, constraint)
+}
+
+// mariadbTableChecks moves the checks of the columns of a table to the
+// constraints of the table, named after the constraint naming convention.
+func mariadbTableChecks(table schema.Table) schema.Table {
+	columns := make([]schema.Column, 0, len(table.Columns))
+	var checks []schema.TableConstraint
+	for _, column := range table.Columns {
+		constraints := make(schema.Constraints, 0, len(column.Constraints))
+		for _, constraint := range column.Constraints {
+			if c, ok := constraint.(schema.CheckConstraint); ok {
+				checks = append(checks, schema.TableConstraint{
+					Name:       constraintName(table, column, "check"),
+					Type:       schema.CheckConstraintType,
+					Columns:    []string{column.Name},
+					Expression: c.Expression,
+				})
+			} else {
+				constraints = append(constraints, constraint)
+			}
+		}
+		column.Constraints = constraints
+		columns = append(columns, column)
+	}
+	table.Columns = columns
+	table.Constraints = append(checks, table.Constraints...)
+	return table
+}
+
+// mariadbLogicalTypes maps logical types to mariadb types. JSON is an alias
+// of LONGTEXT in mariadb.
+var mariadbLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INT"},
+	schema.Int64Type:     {plain: "BIGINT"},
+	schema.StringType:    {plain: "TEXT", parameterized: "VARCHAR(%s)"},
+	schema.DecimalType:   {plain: "DECIMAL", parameterized: "DECIMAL(%s)"},
+	schema.BoolType:      {plain: "TINYINT(1)"},
+	schema.TimestampType: {plain: "DATETIME"},
+	schema.UUIDType:      {plain: "UUID"},
+	schema.JSONType:      {plain: "LONGTEXT"},
+	schema.BytesType:     {plain: "LONGBLOB"},
+}
+
+var mariadbTypeRules = typeRules{
+	logical:   mariadbLogicalTypes,
+	aliases:   mariadbAliases(),
+	unsigned:  true,
+	normalize: mysqlIntegerWidth,
+}
+
+func mariadbAliases() map[string]schema.DataType {
+	aliases := maps.Clone(mysqlTypeRules.aliases)
+	aliases["JSON"] = "LONGTEXT"
+	return aliases
+}
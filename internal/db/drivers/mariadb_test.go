@@ -0,0 +1,59 @@
+package drivers
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestMariadbGetTable(t *testing.T) {
+	db := sql.OpenDB(fakeDatabase{
+		"FROM information_schema.COLUMNS": {
+			{"id", "int(11)", "int", "NO", nil, "auto_increment"},
+			{"email", "varchar(255)", "varchar", "NO", nil, ""},
+			{"status", "varchar(16)", "varchar", "YES", "'active'", ""},
+			{"note", "text", "text", "YES", "NULL", ""},
+			{"settings", "longtext", "longtext", "YES", nil, ""},
+			{"created_at", "timestamp", "timestamp", "NO", "current_timestamp()", ""},
+		},
+		"JOIN information_schema.KEY_COLUMN_USAGE": {
+			{"PRIMARY", "PRIMARY KEY", "id", "", "", "", ""},
+			{"email", "UNIQUE", "email", "", "", "", ""},
+		},
+		"FROM information_schema.CHECK_CONSTRAINTS": {
+			{"settings", "json_valid(`settings`)"},
+			{"status", "`status` <> ''"},
+			{"users_status_check", "`status` in ('active','disabled')"},
+		},
+		"FROM information_schema.STATISTICS": {},
+	})
+	defer db.Close()
+	d := &mariadbDriver{mysqlDriver: &mysqlDriver{db: db}}
+
+	expected := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INT(11)", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.AutoIncrementConstraint{}, schema.PrimaryKeyConstraint{}}},
+			{Name: "email", Type: "VARCHAR(255)", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.UniqueConstraint{}}},
+			{Name: "status", Type: "VARCHAR(16)", Constraints: schema.Constraints{
+				schema.DefaultConstraint{Value: "'active'"},
+				schema.CheckConstraint{Expression: "status in ('active','disabled')"},
+			}},
+			{Name: "note", Type: "TEXT"},
+			{Name: "settings", Type: "LONGTEXT"},
+			{Name: "created_at", Type: "TIMESTAMP", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.DefaultConstraint{Value: "current_timestamp()"}}},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "status", Type: schema.CheckConstraintType, Expression: "status <> ''"},
+		},
+	}
+	table, err := d.GetTable("users")
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Errorf("GetTable() = %+v, want %+v", table, expected)
+	}
+}
@@ -3,6 +3,8 @@ package drivers
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/yassirdeveloper/cli/errors"
@@ -10,15 +12,22 @@ import (
 	"github.com/yassirdeveloper/migrater/internal/utils"
 )
 
+// mysqlDriver manages MySQL servers. name and checksSince are the product
+// and the first version enforcing check constraints, which differ on the
+// drivers embedding it.
 type mysqlDriver struct {
-	version   float32
-	dataTypes []schema.DataType
-	db        *sql.DB
+	name         string
+	version      Version
+	checksSince  Version
+	dataTypes    []schema.DataType
+	typeVersions map[schema.DataType]Version
+	db           *sql.DB
+	tx           *sql.Tx
 	*mysql.MySQLDriver
 }
 
 func (d *mysqlDriver) GetDataTypes() []schema.DataType {
-	return d.dataTypes
+	return availableTypes(d.dataTypes, d.typeVersions, d.version)
 }
 
 func (d *mysqlDriver) Connect(dsn utils.DSN) errors.Error {
@@ -29,12 +38,23 @@ func (d *mysqlDriver) Connect(dsn utils.DSN) errors.Error {
 	if err != nil {
 		return errors.New(fmt.Sprintf("Could not establish connection!\n%s", err))
 	}
+	version, err_ := serverVersion(db, "SELECT VERSION()")
+	if err_ != nil {
+		db.Close()
+		return err_
+	}
 	d.db = db
+	d.version = version
 	return nil
 }
 
 func (d *mysqlDriver) Execute(query string) errors.Error {
-	_, err := d.db.Exec(query)
+	var err error
+	if d.tx != nil {
+		_, err = d.tx.Exec(query)
+	} else {
+		_, err = d.db.Exec(query)
+	}
 	if err != nil {
 		return errors.NewUnexpectedError(err)
 	}
@@ -42,15 +62,57 @@ func (d *mysqlDriver) Execute(query string) errors.Error {
 }
 
 func (d *mysqlDriver) Query(query string) (Result, errors.Error) {
-	rows, err := d.db.Query(query)
+	var rows *sql.Rows
+	var err error
+	if d.tx != nil {
+		rows, err = d.tx.Query(query)
+	} else {
+		rows, err = d.db.Query(query)
+	}
 	if err != nil {
 		return nil, errors.NewUnexpectedError(err)
 	}
 	return rows, nil
 }
 
+func (d *mysqlDriver) Begin() errors.Error {
+	if d.tx != nil {
+		return errors.New("transaction already started")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	d.tx = tx
+	return nil
+}
+
+func (d *mysqlDriver) Commit() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to commit")
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *mysqlDriver) Rollback() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to rollback")
+	}
+	err := d.tx.Rollback()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
 func (d *mysqlDriver) GetTableNames() ([]string, errors.Error) {
-	query := "SHOW TABLES"
+	query := "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, errors.NewUnexpectedError(err)
@@ -73,44 +135,246 @@ func (d *mysqlDriver) GetTableNames() ([]string, errors.Error) {
 }
 
 func (d *mysqlDriver) GetTable(tableName string) (schema.Table, errors.Error) {
-	query := fmt.Sprintf("SHOW COLUMNS FROM `%s`", tableName)
-	rows, err := d.db.Query(query)
+	return d.getTable(tableName, d.columnDefault, d.getCheckConstraints)
+}
+
+// getTable reads a table, converting the reported defaults with
+// columnDefault and reading its checks with checkConstraints, which differ
+// on the drivers embedding mysqlDriver.
+func (d *mysqlDriver) getTable(
+	tableName string,
+	columnDefault func(dataType string, value string, extra string) string,
+	checkConstraints func(tableName string, columns []schema.Column) ([]schema.TableConstraint, errors.Error),
+) (schema.Table, errors.Error) {
+	columns, err := d.getColumns(tableName, columnDefault)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	keyConstraints, err := d.getKeyConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	checks, err := checkConstraints(tableName, columns)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	indexes, err := d.getIndexes(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	columnConstraints, tableConstraints := splitConstraints(d, tableName, append(keyConstraints, checks...))
+	for i := range columns {
+		columns[i].Constraints = append(columns[i].Constraints, columnConstraints[columns[i].Name]...)
+	}
+	return schema.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Constraints: tableConstraints,
+		Indexes:     indexes,
+	}, nil
+}
+
+// getColumns returns the columns of a table with their NOT NULL, DEFAULT
+// and AUTO_INCREMENT constraints, converting the reported defaults with
+// columnDefault. A NULL default is no default.
+func (d *mysqlDriver) getColumns(tableName string, columnDefault func(dataType string, value string, extra string) string) ([]schema.Column, errors.Error) {
+	query := `SELECT COLUMN_NAME, COLUMN_TYPE, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA
+	FROM information_schema.COLUMNS
+	WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	ORDER BY ORDINAL_POSITION`
+	rows, err := d.db.Query(query, tableName)
 	if err != nil {
-		return schema.Table{}, errors.NewUnexpectedError(err)
+		return nil, errors.NewUnexpectedError(err)
 	}
 	defer rows.Close()
 
 	var columns []schema.Column
 	for rows.Next() {
 		var column schema.Column
-		var isPrimaryKey string
-		var columnType, isNullable, defaultValue, extra string
-		if err := rows.Scan(&column.Name, &columnType, &isNullable, &isPrimaryKey, &defaultValue, &extra); err != nil {
-			return schema.Table{}, errors.NewUnexpectedError(err)
+		var columnType, dataType, isNullable, extra string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&column.Name, &columnType, &dataType, &isNullable, &defaultValue, &extra); err != nil {
+			return nil, errors.NewUnexpectedError(err)
 		}
-		column.Type = schema.DataType(column.Type)
+		column.Type = schema.DataType(strings.ToUpper(columnType))
 		if isNullable == "NO" {
 			column.Constraints = append(column.Constraints, schema.NotNullConstraint{})
 		}
-		if isPrimaryKey == "PRI" {
-			column.Constraints = append(column.Constraints, schema.PrimaryKeyConstraint{})
+		if defaultValue.Valid {
+			if value := columnDefault(dataType, defaultValue.String, extra); value != "NULL" {
+				column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: value})
+			}
 		}
-		if defaultValue != "" {
-			column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: defaultValue})
+		if strings.Contains(strings.ToLower(extra), "auto_increment") {
+			column.Constraints = append(column.Constraints, schema.AutoIncrementConstraint{})
 		}
 		columns = append(columns, column)
 	}
 	if err := rows.Err(); err != nil {
-		return schema.Table{}, errors.NewUnexpectedError(err)
+		return nil, errors.NewUnexpectedError(err)
 	}
+	return columns, nil
+}
 
-	return schema.Table{
-		Name:    tableName,
-		Columns: columns,
-	}, nil
+// columnDefault returns a default value as it is written in a column
+// definition. information_schema reports literal defaults unquoted, while
+// expressions are flagged as DEFAULT_GENERATED in mysql 8.
+func (d *mysqlDriver) columnDefault(dataType string, value string, extra string) string {
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") {
+		return value
+	}
+	if strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+		return value
+	}
+	switch strings.ToLower(dataType) {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "decimal", "numeric", "float", "double", "bit", "year":
+		return value
+	}
+	return d.QuoteLiteral(value)
+}
+
+// getKeyConstraints returns the primary key, unique and foreign key
+// constraints of a table. Unique indexes are unique constraints in mysql.
+func (d *mysqlDriver) getKeyConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME,
+		COALESCE(kcu.REFERENCED_TABLE_NAME, ''), COALESCE(kcu.REFERENCED_COLUMN_NAME, ''),
+		COALESCE(rc.UPDATE_RULE, ''), COALESCE(rc.DELETE_RULE, '')
+	FROM information_schema.TABLE_CONSTRAINTS tc
+	JOIN information_schema.KEY_COLUMN_USAGE kcu
+		ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		AND kcu.TABLE_NAME = tc.TABLE_NAME
+	LEFT JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+		ON rc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND rc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		AND rc.TABLE_NAME = tc.TABLE_NAME
+	WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.TABLE_NAME = ?
+		AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+	ORDER BY tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, constraintType, column, referencedTable, referencedColumn, updateRule, deleteRule string
+		if err := rows.Scan(&name, &constraintType, &column, &referencedTable, &referencedColumn, &updateRule, &deleteRule); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraints = appendKeyColumn(constraints, schema.TableConstraint{
+			Name:            name,
+			Type:            keyConstraintType(constraintType),
+			ReferencedTable: referencedTable,
+			OnDelete:        referentialAction(deleteRule),
+			OnUpdate:        referentialAction(updateRule),
+		}, column, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// getIndexes returns the non unique indexes of a table, leaving out the
+// indexes mysql creates for foreign keys.
+func (d *mysqlDriver) getIndexes(tableName string) ([]schema.Index, errors.Error) {
+	query := `SELECT s.INDEX_NAME, s.INDEX_TYPE, s.COLUMN_NAME
+	FROM information_schema.STATISTICS s
+	WHERE s.TABLE_SCHEMA = DATABASE() AND s.TABLE_NAME = ? AND s.NON_UNIQUE = 1
+		AND s.COLUMN_NAME IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM information_schema.TABLE_CONSTRAINTS tc
+			WHERE tc.TABLE_SCHEMA = s.TABLE_SCHEMA AND tc.TABLE_NAME = s.TABLE_NAME
+				AND tc.CONSTRAINT_NAME = s.INDEX_NAME AND tc.CONSTRAINT_TYPE = 'FOREIGN KEY'
+		)
+	ORDER BY s.INDEX_NAME, s.SEQ_IN_INDEX`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var indexes []schema.Index
+	for rows.Next() {
+		var name, indexType, column string
+		if err := rows.Scan(&name, &indexType, &column); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			index := schema.Index{Name: name}
+			if !strings.EqualFold(indexType, "BTREE") {
+				index.Method = strings.ToLower(indexType)
+			}
+			indexes = append(indexes, index)
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return indexes, nil
+}
+
+// getCheckConstraints returns the check constraints of a table. mysql does
+// not record the columns of a check, a check following the constraint naming
+// convention of one of the columns is attributed to it. Servers older than
+// 8.0.16 ignore checks and have no CHECK_CONSTRAINTS.
+func (d *mysqlDriver) getCheckConstraints(tableName string, columns []schema.Column) ([]schema.TableConstraint, errors.Error) {
+	var hasChecks int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'CHECK_CONSTRAINTS'`).Scan(&hasChecks)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	if hasChecks == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+	FROM information_schema.CHECK_CONSTRAINTS cc
+	JOIN information_schema.TABLE_CONSTRAINTS tc
+		ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME
+	WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE = 'CHECK'
+	ORDER BY cc.CONSTRAINT_NAME`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	table := schema.Table{Name: tableName}
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, clause string
+		if err := rows.Scan(&name, &clause); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraint := schema.TableConstraint{Name: name, Type: schema.CheckConstraintType, Expression: mysqlCheckExpression(clause)}
+		for _, column := range columns {
+			if name == constraintName(table, column, "check") {
+				constraint.Columns = []string{column.Name}
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+var mysqlCharsetIntroducer = regexp.MustCompile("(^|[^a-zA-Z0-9_])_[a-z0-9]+'")
+
+// mysqlCheckExpression converts a check clause as stored by mysql, e.g.
+// (`status` in (_utf8mb4'active')), back to the expression it was declared
+// with.
+func mysqlCheckExpression(clause string) string {
+	expression := strings.ReplaceAll(clause, "`", "")
+	expression = mysqlCharsetIntroducer.ReplaceAllString(expression, "$1'")
+	return checkExpression(expression)
 }
 
-func (d *mysqlDriver) Version() float32 {
+func (d *mysqlDriver) Version() Version {
 	return d.version
 }
 
@@ -125,39 +389,51 @@ func (d *mysqlDriver) Close() errors.Error {
 	return nil
 }
 
+var mysqlDataTypes = []schema.DataType{
+	"BIT",
+	"TINYINT",
+	"SMALLINT",
+	"MEDIUMINT",
+	"INT",
+	"BIGINT",
+	"FLOAT",
+	"DOUBLE",
+	"DECIMAL",
+	"CHAR",
+	"VARCHAR",
+	"BINARY",
+	"VARBINARY",
+	"TINYBLOB",
+	"BLOB",
+	"MEDIUMBLOB",
+	"LONGBLOB",
+	"TINYTEXT",
+	"TEXT",
+	"MEDIUMTEXT",
+	"LONGTEXT",
+	"ENUM",
+	"SET",
+	"DATE",
+	"TIME",
+	"TIMESTAMP",
+	"DATETIME",
+	"YEAR",
+	"JSON",
+	"GEOMETRY",
+	"POINT",
+	"LINESTRING",
+	"POLYGON",
+	"GEOMETRYCOLLECTION",
+	"MULTIPOINT",
+	"MULTILINESTRING",
+	"MULTIPOLYGON",
+	"GEOMETRYCOLLECTION",
+}
+
 var mysqlDriverInstance = &mysqlDriver{
-	version: 5.7,
-	dataTypes: []schema.DataType{
-		"BIT",
-		"TINYINT",
-		"SMALLINT",
-		"MEDIUMINT",
-		"INT",
-		"BIGINT",
-		"FLOAT",
-		"DOUBLE",
-		"DECIMAL",
-		"CHAR",
-		"VARCHAR",
-		"BINARY",
-		"VARBINARY",
-		"BLOB",
-		"TEXT",
-		"ENUM",
-		"SET",
-		"DATE",
-		"TIME",
-		"TIMESTAMP",
-		"DATETIME",
-		"YEAR",
-		"GEOMETRY",
-		"POINT",
-		"LINESTRING",
-		"POLYGON",
-		"GEOMETRYCOLLECTION",
-		"MULTIPOINT",
-		"MULTILINESTRING",
-		"MULTIPOLYGON",
-		"GEOMETRYCOLLECTION",
-	},
+	name:         "mysql",
+	version:      Version{Major: 8, Minor: 0, Patch: 16},
+	checksSince:  Version{Major: 8, Minor: 0, Patch: 16},
+	dataTypes:    mysqlDataTypes,
+	typeVersions: map[schema.DataType]Version{"JSON": {Major: 5, Minor: 7, Patch: 8}},
 }
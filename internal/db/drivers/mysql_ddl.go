@@ -0,0 +1,312 @@
+package drivers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// SupportsTransactionalDDL is false since mysql implicitly commits the
+// current transaction on every DDL statement.
+func (d *mysqlDriver) SupportsTransactionalDDL() bool {
+	return false
+}
+
+func (d *mysqlDriver) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "`")
+}
+
+// QuoteLiteral also escapes backslashes, which mysql treats as escape
+// characters in string literals by default.
+func (d *mysqlDriver) QuoteLiteral(value string) string {
+	return quoteLiteral(strings.ReplaceAll(value, `\`, `\\`))
+}
+
+func (d *mysqlDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return mysqlTypeRules.resolveType(t)
+}
+
+func (d *mysqlDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return mysqlTypeRules.normalizeType(t)
+}
+
+// AutoIncrementClause is inherited by mariadb.
+func (d *mysqlDriver) AutoIncrementClause() string {
+	return " AUTO_INCREMENT"
+}
+
+func (d *mysqlDriver) PrimaryKeyClause(column schema.Column) string {
+	return " PRIMARY KEY"
+}
+
+// UniqueConstraintName is the name of the column, which mysql gives to the
+// index backing an inline UNIQUE.
+func (d *mysqlDriver) UniqueConstraintName(table schema.Table, column schema.Column) string {
+	return column.Name
+}
+
+// LargeTextType is LONGTEXT, TEXT holds at most 64KB. It is inherited by
+// mariadb.
+func (d *mysqlDriver) LargeTextType() schema.DataType {
+	return "LONGTEXT"
+}
+
+// columnDefinition renders a column for CREATE, ADD and MODIFY COLUMN.
+// MODIFY COLUMN restates the whole column, key constraints are left out
+// since they are kept by mysql and would otherwise be declared twice.
+func (d *mysqlDriver) columnDefinition(table schema.Table, column schema.Column, withKeys bool) string {
+	return fmt.Sprintf("%s %s%s", d.QuoteIdentifier(column.Name), column.Type, inlineConstraints(d, table, column, withKeys))
+}
+
+func (d *mysqlDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	if hasChecks(table) {
+		if err := d.requireChecks(); err != nil {
+			return nil, err
+		}
+	}
+	columnDefinition := func(table schema.Table, column schema.Column) string {
+		return d.columnDefinition(table, column, true)
+	}
+	return []string{createTable(d, table, columnDefinition)}, nil
+}
+
+func (d *mysqlDriver) DropTable(table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *mysqlDriver) RenameTable(previousName string, table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("RENAME TABLE %s TO %s", d.QuoteIdentifier(previousName), d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *mysqlDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	if hasChecks(schema.Table{Columns: []schema.Column{column}}) {
+		if err := d.requireChecks(); err != nil {
+			return nil, err
+		}
+	}
+	tableName := d.QuoteIdentifier(table.Name)
+	statements := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName, d.columnDefinition(table, column, true))}
+	// mysql silently ignores inline REFERENCES clauses
+	for _, constraint := range column.Constraints {
+		if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, foreignKeyClause(d, table, column, fk)))
+		}
+	}
+	return statements, nil
+}
+
+func (d *mysqlDriver) DropColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(column.Name))}, nil
+}
+
+// mysqlRenameColumnSince is the version introducing RENAME COLUMN.
+var mysqlRenameColumnSince = Version{Major: 8, Minor: 0, Patch: 0}
+
+// RenameColumn restates the column with CHANGE COLUMN on servers older than
+// 8.0, which have no RENAME COLUMN.
+func (d *mysqlDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	if !d.version.AtLeast(mysqlRenameColumnSince) {
+		return []string{d.changeColumn(table, previousName, column)}, nil
+	}
+	return []string{renameColumn(d, table, previousName, column)}, nil
+}
+
+func (d *mysqlDriver) changeColumn(table schema.Table, previousName string, column schema.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(previousName), d.columnDefinition(table, column, false))
+}
+
+func (d *mysqlDriver) AlterColumnType(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{d.modifyColumn(table, column)}, nil
+}
+
+func (d *mysqlDriver) modifyColumn(table schema.Table, column schema.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", d.QuoteIdentifier(table.Name), d.columnDefinition(table, column, false))
+}
+
+func (d *mysqlDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	var sql string
+	switch c := constraint.(type) {
+	case schema.NotNullConstraint, schema.AutoIncrementConstraint:
+		sql = d.modifyColumn(table, column)
+	case schema.DefaultConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", tableName, columnName, c.Value)
+	case schema.PrimaryKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", tableName, columnName)
+	case schema.UniqueConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD UNIQUE KEY %s (%s)", tableName, d.QuoteIdentifier(d.UniqueConstraintName(table, column)), columnName)
+	case schema.CheckConstraint:
+		if err := d.requireChecks(); err != nil {
+			return nil, err
+		}
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)", tableName, d.QuoteIdentifier(constraintName(table, column, "check")), c.Expression)
+	case schema.ForeignKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, foreignKeyClause(d, table, column, c))
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{sql}, nil
+}
+
+func (d *mysqlDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	var sql string
+	switch constraint.(type) {
+	case schema.NotNullConstraint, schema.AutoIncrementConstraint:
+		sql = d.modifyColumn(table, column)
+	case schema.DefaultConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName)
+	case schema.PrimaryKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", tableName)
+	case schema.UniqueConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", tableName, d.QuoteIdentifier(d.UniqueConstraintName(table, column)))
+	case schema.CheckConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", tableName, d.QuoteIdentifier(constraintName(table, column, "check")))
+	case schema.ForeignKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", tableName, d.QuoteIdentifier(constraintName(table, column, "fkey")))
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{sql}, nil
+}
+
+func (d *mysqlDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	if constraint.Type == schema.CheckConstraintType {
+		if err := d.requireChecks(); err != nil {
+			return nil, err
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", d.QuoteIdentifier(table.Name), tableConstraintClause(d, constraint))}, nil
+}
+
+func (d *mysqlDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	name := d.QuoteIdentifier(constraint.Name)
+	var sql string
+	switch constraint.Type {
+	case schema.PrimaryKeyConstraintType:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", tableName)
+	case schema.UniqueConstraintType:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", tableName, name)
+	case schema.ForeignKeyConstraintType:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", tableName, name)
+	case schema.CheckConstraintType:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", tableName, name)
+	default:
+		return nil, unsupportedTableConstraint(table, constraint)
+	}
+	return []string{sql}, nil
+}
+
+// requireChecks refuses check constraints on servers older than
+// checksSince, which parse and silently ignore them.
+func (d *mysqlDriver) requireChecks() errors.Error {
+	if d.version.AtLeast(d.checksSince) {
+		return nil
+	}
+	return unsupportedFeature(d.name, d.version, "check constraints", d.checksSince)
+}
+
+// hasChecks reports whether a table declares check constraints, on its
+// columns or on the table.
+func hasChecks(table schema.Table) bool {
+	for _, column := range table.Columns {
+		for _, constraint := range column.Constraints {
+			if _, ok := constraint.(schema.CheckConstraint); ok {
+				return true
+			}
+		}
+	}
+	return slices.ContainsFunc(table.Constraints, func(constraint schema.TableConstraint) bool {
+		return constraint.Type == schema.CheckConstraintType
+	})
+}
+
+// CreateIndex renders fulltext and spatial indexes as their own index kind,
+// other methods with a USING clause.
+func (d *mysqlDriver) CreateIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	if index.Where != "" {
+		return nil, errors.New(fmt.Sprintf("mysql does not support partial indexes, index %s has a where clause", index.Name))
+	}
+	method := strings.ToUpper(index.Method)
+	switch method {
+	case "":
+		return []string{createIndex(d, table, index, "")}, nil
+	case "FULLTEXT", "SPATIAL":
+		if index.Unique {
+			return nil, errors.New(fmt.Sprintf("mysql %s index %s cannot be unique", index.Method, index.Name))
+		}
+		return []string{strings.Replace(createIndex(d, table, index, ""), "CREATE INDEX", "CREATE "+method+" INDEX", 1)}, nil
+	default:
+		return []string{createIndex(d, table, index, "") + " USING " + method}, nil
+	}
+}
+
+func (d *mysqlDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP INDEX %s ON %s", d.QuoteIdentifier(index.Name), d.QuoteIdentifier(table.Name))}, nil
+}
+
+// IndexNamesPerTable and UniqueIndexesAsConstraints are inherited by
+// mariadb.
+func (d *mysqlDriver) IndexNamesPerTable() bool {
+	return true
+}
+
+func (d *mysqlDriver) UniqueIndexesAsConstraints() bool {
+	return true
+}
+
+// mysqlLogicalTypes maps logical types to mysql types, booleans being
+// stored as TINYINT(1) as mysql does itself.
+var mysqlLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INT"},
+	schema.Int64Type:     {plain: "BIGINT"},
+	schema.StringType:    {plain: "TEXT", parameterized: "VARCHAR(%s)"},
+	schema.DecimalType:   {plain: "DECIMAL", parameterized: "DECIMAL(%s)"},
+	schema.BoolType:      {plain: "TINYINT(1)"},
+	schema.TimestampType: {plain: "DATETIME"},
+	schema.UUIDType:      {plain: "CHAR(36)"},
+	schema.JSONType:      {plain: "JSON"},
+	schema.BytesType:     {plain: "LONGBLOB"},
+}
+
+var mysqlTypeRules = typeRules{
+	logical: mysqlLogicalTypes,
+	aliases: map[string]schema.DataType{
+		"INTEGER":           "INT",
+		"INT4":              "INT",
+		"INT8":              "BIGINT",
+		"BOOL":              "TINYINT(1)",
+		"BOOLEAN":           "TINYINT(1)",
+		"DEC":               "DECIMAL",
+		"NUMERIC":           "DECIMAL",
+		"FIXED":             "DECIMAL",
+		"REAL":              "DOUBLE",
+		"DOUBLE PRECISION":  "DOUBLE",
+		"CHARACTER":         "CHAR",
+		"CHARACTER VARYING": "VARCHAR",
+	},
+	unsigned:  true,
+	normalize: mysqlIntegerWidth,
+}
+
+// mysqlIntegerWidth drops the display width of integer types, which mysql 8
+// no longer reports, except for TINYINT(1) which stands for booleans.
+func mysqlIntegerWidth(parsed schema.ParsedType) schema.ParsedType {
+	switch parsed.Base {
+	case "TINYINT":
+		if len(parsed.Arguments) == 1 && parsed.Arguments[0] == "1" {
+			return parsed
+		}
+	case "SMALLINT", "MEDIUMINT", "INT", "BIGINT":
+	default:
+		return parsed
+	}
+	parsed.Arguments = nil
+	return parsed
+}
@@ -0,0 +1,40 @@
+package drivers
+
+import "testing"
+
+func TestMysqlColumnDefault(t *testing.T) {
+	tests := []struct {
+		dataType string
+		value    string
+		extra    string
+		want     string
+	}{
+		{dataType: "varchar", value: "active", want: "'active'"},
+		{dataType: "varchar", value: "it's", want: "'it''s'"},
+		{dataType: "int", value: "0", want: "0"},
+		{dataType: "decimal", value: "1.50", want: "1.50"},
+		{dataType: "timestamp", value: "CURRENT_TIMESTAMP", extra: "DEFAULT_GENERATED", want: "CURRENT_TIMESTAMP"},
+		{dataType: "datetime", value: "CURRENT_TIMESTAMP(6)", want: "CURRENT_TIMESTAMP(6)"},
+		{dataType: "json", value: "(json_array())", extra: "DEFAULT_GENERATED", want: "(json_array())"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := mysqlDriverInstance.columnDefault(tt.dataType, tt.value, tt.extra); got != tt.want {
+				t.Errorf("columnDefault() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMysqlCheckExpression(t *testing.T) {
+	tests := map[string]string{
+		"(`price` > 0)": "price > 0",
+		"(`status` in (_utf8mb4'active',_utf8mb4'disabled'))": "status in ('active','disabled')",
+		"((`a` > 0) and (`b` > 0))":                           "(a > 0) and (b > 0)",
+	}
+	for clause, want := range tests {
+		if got := mysqlCheckExpression(clause); got != want {
+			t.Errorf("mysqlCheckExpression(%q) = %q, want %q", clause, got, want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package drivers
 
 import (
+	"database/sql"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/jackc/pgx"
 	"github.com/yassirdeveloper/cli/errors"
@@ -10,13 +13,15 @@ import (
 )
 
 type postgresDriver struct {
-	version   float32
-	dataTypes []schema.DataType
-	conn      *pgx.Conn
+	version      Version
+	dataTypes    []schema.DataType
+	typeVersions map[schema.DataType]Version
+	conn         *pgx.Conn
+	tx           *pgx.Tx
 }
 
 func (d *postgresDriver) GetDataTypes() []schema.DataType {
-	return d.dataTypes
+	return availableTypes(d.dataTypes, d.typeVersions, d.version)
 }
 
 func (d *postgresDriver) Connect(dsn utils.DSN) errors.Error {
@@ -32,13 +37,33 @@ func (d *postgresDriver) Connect(dsn utils.DSN) errors.Error {
 		if err_ != nil {
 			return errors.New(fmt.Sprintf("Could not establish connection!\n%s", err_))
 		}
+		var versionNumber int
+		if err := conn.QueryRow("SELECT current_setting('server_version_num')::int").Scan(&versionNumber); err != nil {
+			conn.Close()
+			return errors.New(fmt.Sprintf("Could not query server version!\n%s", err))
+		}
 		d.conn = conn
+		d.version = postgresVersion(versionNumber)
 	}
 	return nil
 }
 
+// postgresVersion converts a server_version_num, e.g. 150004 for 15.4 or
+// 90624 for 9.6.24.
+func postgresVersion(number int) Version {
+	if number >= 100000 {
+		return Version{Major: number / 10000, Minor: number % 10000}
+	}
+	return Version{Major: number / 10000, Minor: number / 100 % 100, Patch: number % 100}
+}
+
 func (d *postgresDriver) Execute(query string) errors.Error {
-	_, err := d.conn.Exec(query)
+	var err error
+	if d.tx != nil {
+		_, err = d.tx.Exec(query)
+	} else {
+		_, err = d.conn.Exec(query)
+	}
 	if err != nil {
 		return errors.NewUnexpectedError(err)
 	}
@@ -46,15 +71,67 @@ func (d *postgresDriver) Execute(query string) errors.Error {
 }
 
 func (d *postgresDriver) Query(query string) (Result, errors.Error) {
-	rows, err := d.conn.Query(query)
+	var rows *pgx.Rows
+	var err error
+	if d.tx != nil {
+		rows, err = d.tx.Query(query)
+	} else {
+		rows, err = d.conn.Query(query)
+	}
 	if err != nil {
 		return nil, errors.NewUnexpectedError(err)
 	}
-	return rows, nil
+	return pgxResult{rows}, nil
+}
+
+// pgxResult adapts pgx rows to Result, their Close does not return an error.
+type pgxResult struct {
+	*pgx.Rows
+}
+
+func (r pgxResult) Close() error {
+	r.Rows.Close()
+	return nil
+}
+
+func (d *postgresDriver) Begin() errors.Error {
+	if d.tx != nil {
+		return errors.New("transaction already started")
+	}
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	d.tx = tx
+	return nil
+}
+
+func (d *postgresDriver) Commit() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to commit")
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *postgresDriver) Rollback() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to rollback")
+	}
+	err := d.tx.Rollback()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
 }
 
 func (d *postgresDriver) GetTableNames() ([]string, errors.Error) {
-	query := "SELECT table_name FROM information_schema.tables WHERE table_schema = 'public'"
+	query := "SELECT table_name FROM information_schema.tables WHERE table_schema = 'public' AND table_type = 'BASE TABLE'"
 	rows, err := d.conn.Query(query)
 	if err != nil {
 		return nil, errors.NewUnexpectedError(err)
@@ -73,29 +150,259 @@ func (d *postgresDriver) GetTableNames() ([]string, errors.Error) {
 }
 
 func (d *postgresDriver) GetTable(tableName string) (schema.Table, errors.Error) {
-	query := fmt.Sprintf("SELECT column_name, data_type FROM information_schema.columns WHERE table_name = '%s'", tableName)
-	rows, err := d.conn.Query(query)
+	columns, err := d.getColumns(tableName)
 	if err != nil {
-		return schema.Table{}, errors.NewUnexpectedError(err)
+		return schema.Table{}, err
+	}
+	keyConstraints, err := d.getKeyConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	checkConstraints, err := d.getCheckConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	indexes, err := d.getIndexes(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	columnConstraints, tableConstraints := splitConstraints(d, tableName, append(keyConstraints, checkConstraints...))
+	for i := range columns {
+		columns[i].Constraints = append(columns[i].Constraints, columnConstraints[columns[i].Name]...)
+	}
+	return schema.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Constraints: tableConstraints,
+		Indexes:     indexes,
+	}, nil
+}
+
+func (d *postgresDriver) getColumns(tableName string) ([]schema.Column, errors.Error) {
+	query := `SELECT column_name::text, data_type::text, udt_name::text, is_nullable::text, column_default::text,
+		character_maximum_length::int, numeric_precision::int, numeric_scale::int, datetime_precision::int, is_identity::text
+	FROM information_schema.columns
+	WHERE table_schema = 'public' AND table_name = $1
+	ORDER BY ordinal_position`
+	rows, err := d.conn.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
 	}
 	defer rows.Close()
 
 	var columns []schema.Column
 	for rows.Next() {
 		var column schema.Column
-		if err := rows.Scan(&column.Name, &column.Type); err != nil {
-			return schema.Table{}, errors.NewUnexpectedError(err)
+		var dataType, udtName, isNullable, isIdentity string
+		var columnDefault sql.NullString
+		var maxLength, precision, scale, datetimePrecision sql.NullInt64
+		if err := rows.Scan(&column.Name, &dataType, &udtName, &isNullable, &columnDefault, &maxLength, &precision, &scale, &datetimePrecision, &isIdentity); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		column.Type = postgresColumnType(dataType, udtName, maxLength, precision, scale, datetimePrecision)
+		if isNullable == "NO" {
+			column.Constraints = append(column.Constraints, schema.NotNullConstraint{})
+		}
+		if isIdentity == "YES" {
+			column.Constraints = append(column.Constraints, schema.AutoIncrementConstraint{})
+		}
+		if columnDefault.Valid {
+			if serialType, ok := postgresSerialType(column.Type, columnDefault.String); ok {
+				column.Type = serialType
+			} else {
+				column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: postgresDefault(columnDefault.String)})
+			}
 		}
 		columns = append(columns, column)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return columns, nil
+}
 
-	return schema.Table{
-		Name:    tableName,
-		Columns: columns,
-	}, nil
+// getKeyConstraints returns the primary key, unique and foreign key
+// constraints of a table.
+func (d *postgresDriver) getKeyConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT tc.constraint_name::text, tc.constraint_type::text, kcu.column_name::text,
+		COALESCE(rkcu.table_name::text, ''), COALESCE(rkcu.column_name::text, ''),
+		COALESCE(rc.update_rule::text, ''), COALESCE(rc.delete_rule::text, '')
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage kcu
+		ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+		AND kcu.table_name = tc.table_name
+	LEFT JOIN information_schema.referential_constraints rc
+		ON rc.constraint_schema = tc.constraint_schema AND rc.constraint_name = tc.constraint_name
+	LEFT JOIN information_schema.key_column_usage rkcu
+		ON rkcu.constraint_schema = rc.unique_constraint_schema AND rkcu.constraint_name = rc.unique_constraint_name
+		AND rkcu.ordinal_position = kcu.position_in_unique_constraint
+	WHERE tc.table_schema = 'public' AND tc.table_name = $1
+		AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+	ORDER BY tc.constraint_name, kcu.ordinal_position`
+	rows, err := d.conn.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, constraintType, column, referencedTable, referencedColumn, updateRule, deleteRule string
+		if err := rows.Scan(&name, &constraintType, &column, &referencedTable, &referencedColumn, &updateRule, &deleteRule); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraints = appendKeyColumn(constraints, schema.TableConstraint{
+			Name:            name,
+			Type:            keyConstraintType(constraintType),
+			ReferencedTable: referencedTable,
+			OnDelete:        referentialAction(deleteRule),
+			OnUpdate:        referentialAction(updateRule),
+		}, column, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// getCheckConstraints returns the check constraints of a table along with
+// the columns they apply to.
+func (d *postgresDriver) getCheckConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT con.conname::text, pg_get_constraintdef(con.oid),
+		ARRAY(SELECT a.attname FROM pg_attribute a WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey) ORDER BY a.attnum)::text[]
+	FROM pg_constraint con
+	JOIN pg_class rel ON rel.oid = con.conrelid
+	JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+	WHERE con.contype = 'c' AND nsp.nspname = 'public' AND rel.relname = $1
+	ORDER BY con.conname`
+	rows, err := d.conn.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		constraint := schema.TableConstraint{Type: schema.CheckConstraintType}
+		var definition string
+		if err := rows.Scan(&constraint.Name, &definition, &constraint.Columns); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraint.Expression = checkExpression(definition)
+		constraints = append(constraints, constraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// getIndexes returns the indexes of a table that do not back a constraint.
+func (d *postgresDriver) getIndexes(tableName string) ([]schema.Index, errors.Error) {
+	query := `SELECT ic.relname::text, i.indisunique, am.amname::text, COALESCE(pg_get_expr(i.indpred, i.indrelid), ''),
+		ARRAY(SELECT pg_get_indexdef(i.indexrelid, k, true) FROM generate_series(1, i.indnatts) k ORDER BY k)::text[]
+	FROM pg_index i
+	JOIN pg_class ic ON ic.oid = i.indexrelid
+	JOIN pg_class rel ON rel.oid = i.indrelid
+	JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+	JOIN pg_am am ON am.oid = ic.relam
+	WHERE nsp.nspname = 'public' AND rel.relname = $1
+		AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = i.indexrelid)
+	ORDER BY ic.relname`
+	rows, err := d.conn.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var indexes []schema.Index
+	for rows.Next() {
+		var index schema.Index
+		var method, where string
+		if err := rows.Scan(&index.Name, &index.Unique, &method, &where, &index.Columns); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if method != "btree" {
+			index.Method = method
+		}
+		index.Where = unwrapParentheses(where)
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return indexes, nil
+}
+
+// postgresColumnType converts an information_schema data type to the type
+// used in a column definition. The fractional seconds precision of time
+// types is left out when it is the default of 6.
+func postgresColumnType(dataType string, udtName string, maxLength, precision, scale, datetimePrecision sql.NullInt64) schema.DataType {
+	var columnType string
+	switch dataType {
+	case "character varying":
+		columnType = "VARCHAR"
+	case "character":
+		columnType = "CHAR"
+	case "bit varying":
+		columnType = "VARBIT"
+	case "timestamp without time zone":
+		columnType = "TIMESTAMP"
+	case "timestamp with time zone":
+		columnType = "TIMESTAMPTZ"
+	case "time without time zone":
+		columnType = "TIME"
+	case "time with time zone":
+		columnType = "TIMETZ"
+	case "USER-DEFINED":
+		columnType = udtName
+	case "ARRAY":
+		columnType = strings.TrimPrefix(udtName, "_") + "[]"
+	default:
+		columnType = dataType
+	}
+	columnType = strings.ToUpper(columnType)
+	switch {
+	case maxLength.Valid:
+		columnType = fmt.Sprintf("%s(%d)", columnType, maxLength.Int64)
+	case dataType == "numeric" && precision.Valid && scale.Valid:
+		columnType = fmt.Sprintf("%s(%d,%d)", columnType, precision.Int64, scale.Int64)
+	case postgresTimeTypes[columnType] && datetimePrecision.Valid && datetimePrecision.Int64 != postgresTimePrecision:
+		columnType = fmt.Sprintf("%s(%d)", columnType, datetimePrecision.Int64)
+	}
+	return schema.DataType(columnType)
+}
+
+// postgresSerialType returns the serial type of an integer column whose
+// default is generated by a sequence.
+func postgresSerialType(columnType schema.DataType, columnDefault string) (schema.DataType, bool) {
+	if !strings.HasPrefix(columnDefault, "nextval(") {
+		return columnType, false
+	}
+	switch {
+	case columnType.Equals("SMALLINT"):
+		return "SMALLSERIAL", true
+	case columnType.Equals("INTEGER"):
+		return "SERIAL", true
+	case columnType.Equals("BIGINT"):
+		return "BIGSERIAL", true
+	default:
+		return columnType, false
+	}
+}
+
+var postgresCast = regexp.MustCompile(`::[a-zA-Z_][a-zA-Z0-9_ ]*(\[\])?$`)
+
+// postgresDefault removes the casts postgres adds to default values, e.g.
+// 'draft'::character varying.
+func postgresDefault(value string) string {
+	for postgresCast.MatchString(value) {
+		value = postgresCast.ReplaceAllString(value, "")
+	}
+	return value
 }
 
-func (d *postgresDriver) Version() float32 {
+func (d *postgresDriver) Version() Version {
 	return d.version
 }
 
@@ -111,7 +418,8 @@ func (d *postgresDriver) Close() errors.Error {
 }
 
 var postgresDriverInstance = &postgresDriver{
-	version: 13.0,
+	version:      Version{Major: 13, Minor: 0, Patch: 0},
+	typeVersions: map[schema.DataType]Version{"JSONB": {Major: 9, Minor: 4, Patch: 0}},
 	dataTypes: []schema.DataType{
 		"SMALLINT",
 		"INTEGER",
@@ -134,6 +442,7 @@ var postgresDriverInstance = &postgresDriver{
 		"TIME",
 		"TIMESTAMP",
 		"TIMESTAMPTZ",
+		"TIMETZ",
 		"INTERVAL",
 		"TIME WITH TIME ZONE",
 		"TIMESTAMP WITH TIME ZONE",
@@ -156,6 +465,8 @@ var postgresDriverInstance = &postgresDriver{
 		"REGTRIGGER",
 		"REGWINDOW",
 		"UUID",
+		"JSON",
+		"JSONB",
 		"XML",
 		"BOX",
 		"CIRCLE",
@@ -0,0 +1,225 @@
+package drivers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func (d *postgresDriver) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (d *postgresDriver) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "\"")
+}
+
+func (d *postgresDriver) QuoteLiteral(value string) string {
+	return quoteLiteral(value)
+}
+
+func (d *postgresDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return postgresTypeRules.resolveType(t)
+}
+
+func (d *postgresDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return postgresTypeRules.normalizeType(t)
+}
+
+// AutoIncrementClause is inherited by cockroachdb.
+func (d *postgresDriver) AutoIncrementClause() string {
+	return " GENERATED BY DEFAULT AS IDENTITY"
+}
+
+func (d *postgresDriver) PrimaryKeyClause(column schema.Column) string {
+	return " PRIMARY KEY"
+}
+
+func (d *postgresDriver) UniqueConstraintName(table schema.Table, column schema.Column) string {
+	return constraintName(table, column, "key")
+}
+
+func (d *postgresDriver) LargeTextType() schema.DataType {
+	return "TEXT"
+}
+
+func (d *postgresDriver) columnDefinition(table schema.Table, column schema.Column) string {
+	return fmt.Sprintf("%s %s%s", d.QuoteIdentifier(column.Name), column.Type, inlineConstraints(d, table, column, true))
+}
+
+func (d *postgresDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	return []string{createTable(d, table, d.columnDefinition)}, nil
+}
+
+func (d *postgresDriver) DropTable(table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *postgresDriver) RenameTable(previousName string, table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", d.QuoteIdentifier(previousName), d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *postgresDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	definition := d.columnDefinition(table, column)
+	for _, constraint := range column.Constraints {
+		if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+			definition += " " + referencesClause(d, fk)
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", d.QuoteIdentifier(table.Name), definition)}, nil
+}
+
+func (d *postgresDriver) DropColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(column.Name))}, nil
+}
+
+func (d *postgresDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	return []string{renameColumn(d, table, previousName, column)}, nil
+}
+
+func (d *postgresDriver) AlterColumnType(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{fmt.Sprintf(
+		"ALTER TABLE %s ALTER COLUMN %s TYPE %s",
+		d.QuoteIdentifier(table.Name),
+		d.QuoteIdentifier(column.Name),
+		column.Type,
+	)}, nil
+}
+
+func (d *postgresDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	var sql string
+	switch c := constraint.(type) {
+	case schema.NotNullConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", tableName, columnName)
+	case schema.AutoIncrementConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY", tableName, columnName)
+	case schema.DefaultConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", tableName, columnName, c.Value)
+	case schema.PrimaryKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", tableName, columnName)
+	case schema.UniqueConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", tableName, d.QuoteIdentifier(constraintName(table, column, "key")), columnName)
+	case schema.CheckConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)", tableName, d.QuoteIdentifier(constraintName(table, column, "check")), c.Expression)
+	case schema.ForeignKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, foreignKeyClause(d, table, column, c))
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{sql}, nil
+}
+
+func (d *postgresDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	var sql string
+	switch constraint.(type) {
+	case schema.NotNullConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", tableName, columnName)
+	case schema.AutoIncrementConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP IDENTITY", tableName, columnName)
+	case schema.DefaultConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName)
+	case schema.PrimaryKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, d.QuoteIdentifier(table.Name+"_pkey"))
+	case schema.UniqueConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, d.QuoteIdentifier(constraintName(table, column, "key")))
+	case schema.CheckConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, d.QuoteIdentifier(constraintName(table, column, "check")))
+	case schema.ForeignKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, d.QuoteIdentifier(constraintName(table, column, "fkey")))
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{sql}, nil
+}
+
+func (d *postgresDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", d.QuoteIdentifier(table.Name), tableConstraintClause(d, constraint))}, nil
+}
+
+func (d *postgresDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(constraint.Name))}, nil
+}
+
+func (d *postgresDriver) CreateIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	method := ""
+	if index.Method != "" {
+		method = " USING " + index.Method
+	}
+	return []string{createIndex(d, table, index, method)}, nil
+}
+
+func (d *postgresDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP INDEX %s", d.QuoteIdentifier(index.Name))}, nil
+}
+
+// IndexNamesPerTable is false, indexes share the namespace of tables.
+func (d *postgresDriver) IndexNamesPerTable() bool {
+	return false
+}
+
+func (d *postgresDriver) UniqueIndexesAsConstraints() bool {
+	return false
+}
+
+// postgresLogicalTypes maps logical types to postgres types, spelled as
+// they are introspected.
+var postgresLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INTEGER"},
+	schema.Int64Type:     {plain: "BIGINT"},
+	schema.StringType:    {plain: "TEXT", parameterized: "VARCHAR(%s)"},
+	schema.DecimalType:   {plain: "NUMERIC", parameterized: "NUMERIC(%s)"},
+	schema.BoolType:      {plain: "BOOLEAN"},
+	schema.TimestampType: {plain: "TIMESTAMP"},
+	schema.UUIDType:      {plain: "UUID"},
+	schema.JSONType:      {plain: "JSONB"},
+	schema.BytesType:     {plain: "BYTEA"},
+}
+
+var postgresTypeRules = typeRules{
+	logical: postgresLogicalTypes,
+	aliases: map[string]schema.DataType{
+		"INT":                         "INTEGER",
+		"INT2":                        "SMALLINT",
+		"INT4":                        "INTEGER",
+		"INT8":                        "BIGINT",
+		"SERIAL2":                     "SMALLSERIAL",
+		"SERIAL4":                     "SERIAL",
+		"SERIAL8":                     "BIGSERIAL",
+		"FLOAT4":                      "REAL",
+		"FLOAT8":                      "DOUBLE PRECISION",
+		"FLOAT":                       "DOUBLE PRECISION",
+		"BOOL":                        "BOOLEAN",
+		"DECIMAL":                     "NUMERIC",
+		"CHARACTER":                   "CHAR",
+		"BPCHAR":                      "CHAR",
+		"CHARACTER VARYING":           "VARCHAR",
+		"BIT VARYING":                 "VARBIT",
+		"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+		"TIMESTAMP WITH TIME ZONE":    "TIMESTAMPTZ",
+		"TIME WITHOUT TIME ZONE":      "TIME",
+		"TIME WITH TIME ZONE":         "TIMETZ",
+	},
+	arrays:    true,
+	normalize: postgresTimePrecisionDefault,
+}
+
+// postgresTimeTypes are the types taking a fractional seconds precision,
+// postgresTimePrecision being the precision they have without one.
+var postgresTimeTypes = map[string]bool{"TIMESTAMP": true, "TIMESTAMPTZ": true, "TIME": true, "TIMETZ": true}
+
+const postgresTimePrecision = 6
+
+// postgresTimePrecisionDefault drops the precision of time types when it is
+// the default, which postgres does not distinguish from no precision.
+func postgresTimePrecisionDefault(parsed schema.ParsedType) schema.ParsedType {
+	if postgresTimeTypes[parsed.Base] && len(parsed.Arguments) == 1 && parsed.Arguments[0] == strconv.Itoa(postgresTimePrecision) {
+		parsed.Arguments = nil
+	}
+	return parsed
+}
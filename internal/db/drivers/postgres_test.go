@@ -0,0 +1,107 @@
+package drivers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestPostgresColumnType(t *testing.T) {
+	tests := []struct {
+		dataType  string
+		udtName   string
+		maxLength sql.NullInt64
+		precision sql.NullInt64
+		scale     sql.NullInt64
+		datetime  sql.NullInt64
+		want      schema.DataType
+	}{
+		{dataType: "integer", udtName: "int4", want: "INTEGER"},
+		{dataType: "character varying", udtName: "varchar", maxLength: sql.NullInt64{Int64: 255, Valid: true}, want: "VARCHAR(255)"},
+		{dataType: "character varying", udtName: "varchar", want: "VARCHAR"},
+		{dataType: "numeric", udtName: "numeric", precision: sql.NullInt64{Int64: 10, Valid: true}, scale: sql.NullInt64{Int64: 2, Valid: true}, want: "NUMERIC(10,2)"},
+		{dataType: "timestamp with time zone", udtName: "timestamptz", datetime: sql.NullInt64{Int64: 6, Valid: true}, want: "TIMESTAMPTZ"},
+		{dataType: "timestamp without time zone", udtName: "timestamp", datetime: sql.NullInt64{Int64: 3, Valid: true}, want: "TIMESTAMP(3)"},
+		{dataType: "time without time zone", udtName: "time", datetime: sql.NullInt64{Int64: 0, Valid: true}, want: "TIME(0)"},
+		{dataType: "USER-DEFINED", udtName: "citext", want: "CITEXT"},
+		{dataType: "ARRAY", udtName: "_text", want: "TEXT[]"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			got := postgresColumnType(tt.dataType, tt.udtName, tt.maxLength, tt.precision, tt.scale, tt.datetime)
+			if got != tt.want {
+				t.Errorf("postgresColumnType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDefault(t *testing.T) {
+	tests := []struct {
+		columnType  schema.DataType
+		value       string
+		wantType    schema.DataType
+		wantDefault string
+	}{
+		{columnType: "INTEGER", value: "nextval('users_id_seq'::regclass)", wantType: "SERIAL"},
+		{columnType: "BIGINT", value: "nextval('users_id_seq'::regclass)", wantType: "BIGSERIAL"},
+		{columnType: "VARCHAR(16)", value: "'draft'::character varying", wantType: "VARCHAR(16)", wantDefault: "'draft'"},
+		{columnType: "TEXT[]", value: "'{}'::text[]", wantType: "TEXT[]", wantDefault: "'{}'"},
+		{columnType: "INTEGER", value: "0", wantType: "INTEGER", wantDefault: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			gotType, serial := postgresSerialType(tt.columnType, tt.value)
+			if gotType != tt.wantType {
+				t.Errorf("postgresSerialType() = %q, want %q", gotType, tt.wantType)
+			}
+			if serial {
+				return
+			}
+			if got := postgresDefault(tt.value); got != tt.wantDefault {
+				t.Errorf("postgresDefault() = %q, want %q", got, tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestCheckExpression(t *testing.T) {
+	tests := map[string]string{
+		"CHECK ((price > 0))":                      "price > 0",
+		"CHECK ((a > 0) AND (b > 0))":              "(a > 0) AND (b > 0)",
+		"CHECK (((status)::text = 'draft'::text))": "(status)::text = 'draft'::text",
+	}
+	for definition, want := range tests {
+		if got := checkExpression(definition); got != want {
+			t.Errorf("checkExpression(%q) = %q, want %q", definition, got, want)
+		}
+	}
+}
+
+// TestPostgresTimePrecisionRoundTrip checks that a desired time type
+// compares equal to the type introspected once the column is created.
+func TestPostgresTimePrecisionRoundTrip(t *testing.T) {
+	tests := []struct {
+		desired   schema.DataType
+		dataType  string
+		precision int64
+	}{
+		{desired: "TIMESTAMP", dataType: "timestamp without time zone", precision: 6},
+		{desired: "TIMESTAMP(6)", dataType: "timestamp without time zone", precision: 6},
+		{desired: "TIMESTAMP(3)", dataType: "timestamp without time zone", precision: 3},
+		{desired: "TIMESTAMP(0) WITH TIME ZONE", dataType: "timestamp with time zone", precision: 0},
+		{desired: "TIMESTAMPTZ(6)", dataType: "timestamp with time zone", precision: 6},
+		{desired: "TIME(3)", dataType: "time without time zone", precision: 3},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.desired), func(t *testing.T) {
+			desired, _ := postgresDriverInstance.NormalizeType(tt.desired)
+			introspected := postgresColumnType(tt.dataType, "", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{Int64: tt.precision, Valid: true})
+			live, _ := postgresDriverInstance.NormalizeType(introspected)
+			if desired.DataType() != live.DataType() {
+				t.Errorf("desired %q normalizes to %q, introspected %q to %q", tt.desired, desired.DataType(), introspected, live.DataType())
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package drivers
 import (
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/yassirdeveloper/cli/errors"
@@ -11,9 +14,10 @@ import (
 )
 
 type sqliteDriver struct {
-	version   float32
+	version   Version
 	dataTypes []schema.DataType
 	db        *sql.DB
+	tx        *sql.Tx
 	*sqlite3.SQLiteDriver
 }
 
@@ -35,12 +39,27 @@ func (d *sqliteDriver) Connect(dsn utils.DSN) errors.Error {
 	if err := db.Ping(); err != nil {
 		return errors.New(fmt.Sprintf("Could not ping database!\n%s", err))
 	}
+	// A single connection keeps the pragmas of a migration and its
+	// transaction on the same connection, sqlite pragmas being set per
+	// connection.
+	db.SetMaxOpenConns(1)
+	version, err_ := serverVersion(db, "SELECT sqlite_version()")
+	if err_ != nil {
+		db.Close()
+		return err_
+	}
 	d.db = db
+	d.version = version
 	return nil
 }
 
 func (d *sqliteDriver) Execute(query string) errors.Error {
-	_, err := d.db.Exec(query)
+	var err error
+	if d.tx != nil {
+		_, err = d.tx.Exec(query)
+	} else {
+		_, err = d.db.Exec(query)
+	}
 	if err != nil {
 		return errors.NewUnexpectedError(err)
 	}
@@ -48,13 +67,55 @@ func (d *sqliteDriver) Execute(query string) errors.Error {
 }
 
 func (d *sqliteDriver) Query(query string) (Result, errors.Error) {
-	rows, err := d.db.Query(query)
+	var rows *sql.Rows
+	var err error
+	if d.tx != nil {
+		rows, err = d.tx.Query(query)
+	} else {
+		rows, err = d.db.Query(query)
+	}
 	if err != nil {
 		return nil, errors.NewUnexpectedError(err)
 	}
 	return rows, nil
 }
 
+func (d *sqliteDriver) Begin() errors.Error {
+	if d.tx != nil {
+		return errors.New("transaction already started")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	d.tx = tx
+	return nil
+}
+
+func (d *sqliteDriver) Commit() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to commit")
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *sqliteDriver) Rollback() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to rollback")
+	}
+	err := d.tx.Rollback()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
 func (d *sqliteDriver) GetTableNames() ([]string, errors.Error) {
 	query := "SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%'"
 	rows, err := d.db.Query(query)
@@ -75,40 +136,563 @@ func (d *sqliteDriver) GetTableNames() ([]string, errors.Error) {
 }
 
 func (d *sqliteDriver) GetTable(tableName string) (schema.Table, errors.Error) {
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
-	rows, err := d.db.Query(query)
+	columns, primaryKey, err := d.getColumns(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	indexes, uniqueColumns, err := d.getIndexes(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	foreignKeys, err := d.getForeignKeys(tableName)
 	if err != nil {
-		return schema.Table{}, errors.NewUnexpectedError(err)
+		return schema.Table{}, err
+	}
+	createSQL, err := d.getCreateSQL(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	declared := sqliteColumnConstraints(createSQL)
+	var tableConstraints []schema.TableConstraint
+	for _, constraint := range sqliteTableConstraints(createSQL) {
+		if isColumnConstraint(d, tableName, constraint) {
+			continue
+		}
+		tableConstraints = append(tableConstraints, constraint)
+		// single column constraints declared on the table are also listed
+		// by the pragmas
+		if len(constraint.Columns) == 1 {
+			column := constraint.Columns[0]
+			switch constraint.Type {
+			case schema.UniqueConstraintType:
+				delete(uniqueColumns, column)
+			case schema.ForeignKeyConstraintType:
+				foreignKeys[column] = slices.DeleteFunc(foreignKeys[column], func(fk schema.Constraint) bool {
+					return fk.(schema.ForeignKeyConstraint).ReferencedTable == constraint.ReferencedTable
+				})
+			}
+		}
+	}
+	for i := range columns {
+		name := columns[i].Name
+		if len(primaryKey) == 1 && primaryKey[0] == name {
+			columns[i].Constraints = append(columns[i].Constraints, schema.PrimaryKeyConstraint{})
+		}
+		if uniqueColumns[name] {
+			columns[i].Constraints = append(columns[i].Constraints, schema.UniqueConstraint{})
+		}
+		columns[i].Constraints = append(columns[i].Constraints, declared[name]...)
+		columns[i].Constraints = append(columns[i].Constraints, foreignKeys[name]...)
+	}
+	return schema.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Constraints: tableConstraints,
+		Indexes:     indexes,
+	}, nil
+}
+
+// getColumns returns the columns of a table with their NOT NULL and DEFAULT
+// constraints, and the names of the primary key columns.
+func (d *sqliteDriver) getColumns(tableName string) ([]schema.Column, []string, errors.Error) {
+	query := `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, nil, errors.NewUnexpectedError(err)
 	}
 	defer rows.Close()
 
 	var columns []schema.Column
+	primaryKey := make(map[int]string)
 	for rows.Next() {
-		var cid int
 		var name string
 		var ctype string
 		var notnull int
 		var dfltValue sql.NullString
 		var pk int
 
-		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
-			return schema.Table{}, errors.NewUnexpectedError(err)
+		if err := rows.Scan(&name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			return nil, nil, errors.NewUnexpectedError(err)
 		}
 
 		column := schema.Column{
-			Name:       name,
-			Type:       schema.DataType(ctype),
+			Name: name,
+			Type: schema.DataType(ctype),
+		}
+		if notnull == 1 {
+			column.Constraints = append(column.Constraints, schema.NotNullConstraint{})
+		}
+		if dfltValue.Valid {
+			column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: dfltValue.String})
+		}
+		if pk > 0 {
+			primaryKey[pk] = name
 		}
 		columns = append(columns, column)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.NewUnexpectedError(err)
+	}
+	primaryKeyColumns := make([]string, 0, len(primaryKey))
+	for i := 1; i <= len(primaryKey); i++ {
+		primaryKeyColumns = append(primaryKeyColumns, primaryKey[i])
+	}
+	return columns, primaryKeyColumns, nil
+}
 
-	return schema.Table{
-		Name:    tableName,
-		Columns: columns,
-	}, nil
+// getIndexes returns the indexes created on a table and the columns with a
+// single column unique constraint. A unique index following the constraint
+// naming convention is reported as a unique constraint, since it is how
+// unique constraints are added to existing sqlite tables.
+func (d *sqliteDriver) getIndexes(tableName string) ([]schema.Index, map[string]bool, errors.Error) {
+	query := `SELECT il.name, il."unique", il.origin, il.partial, COALESCE(m.sql, '')
+	FROM pragma_index_list(?) il
+	LEFT JOIN sqlite_master m ON m.type = 'index' AND m.name = il.name
+	ORDER BY il.name`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	type indexInfo struct {
+		index   schema.Index
+		origin  string
+		partial bool
+	}
+	var infos []indexInfo
+	for rows.Next() {
+		var info indexInfo
+		var createSQL string
+		if err := rows.Scan(&info.index.Name, &info.index.Unique, &info.origin, &info.partial, &createSQL); err != nil {
+			return nil, nil, errors.NewUnexpectedError(err)
+		}
+		if info.partial {
+			info.index.Where = sqliteIndexWhere(createSQL)
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.NewUnexpectedError(err)
+	}
+
+	var indexes []schema.Index
+	uniqueColumns := make(map[string]bool)
+	for _, info := range infos {
+		columns, err := d.getIndexColumns(info.index.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		info.index.Columns = columns
+		singleUnique := info.index.Unique && !info.partial && len(columns) == 1
+		switch {
+		case info.origin == "pk":
+			continue
+		case info.origin == "u":
+			if singleUnique {
+				uniqueColumns[columns[0]] = true
+			}
+			continue
+		case singleUnique && info.index.Name == constraintName(schema.Table{Name: tableName}, schema.Column{Name: columns[0]}, "key"):
+			uniqueColumns[columns[0]] = true
+			continue
+		}
+		indexes = append(indexes, info.index)
+	}
+	return indexes, uniqueColumns, nil
+}
+
+func (d *sqliteDriver) getIndexColumns(indexName string) ([]string, errors.Error) {
+	rows, err := d.db.Query("SELECT name FROM pragma_index_info(?) ORDER BY seqno", indexName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column sql.NullString
+		if err := rows.Scan(&column); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		columns = append(columns, column.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return columns, nil
+}
+
+// getForeignKeys returns the single column foreign keys of a table by column
+// name. A foreign key without a referenced column references the primary
+// key of the referenced table.
+func (d *sqliteDriver) getForeignKeys(tableName string) (map[string][]schema.Constraint, errors.Error) {
+	query := `SELECT id, "table", "from", "to", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	type foreignKey struct {
+		column     string
+		constraint schema.ForeignKeyConstraint
+	}
+	var ids []int
+	keys := make(map[int][]foreignKey)
+	for rows.Next() {
+		var id int
+		var referencedTable, column, onUpdate, onDelete string
+		var referencedColumn sql.NullString
+		if err := rows.Scan(&id, &referencedTable, &column, &referencedColumn, &onUpdate, &onDelete); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if _, ok := keys[id]; !ok {
+			ids = append(ids, id)
+		}
+		keys[id] = append(keys[id], foreignKey{
+			column: column,
+			constraint: schema.ForeignKeyConstraint{
+				ReferencedTable:  referencedTable,
+				ReferencedColumn: referencedColumn.String,
+				OnDelete:         referentialAction(onDelete),
+				OnUpdate:         referentialAction(onUpdate),
+			},
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+
+	foreignKeys := make(map[string][]schema.Constraint)
+	for _, id := range ids {
+		if len(keys[id]) != 1 {
+			continue
+		}
+		key := keys[id][0]
+		if key.constraint.ReferencedColumn == "" {
+			_, primaryKey, err := d.getColumns(key.constraint.ReferencedTable)
+			if err != nil {
+				return nil, err
+			}
+			if len(primaryKey) == 1 {
+				key.constraint.ReferencedColumn = primaryKey[0]
+			}
+		}
+		foreignKeys[key.column] = append(foreignKeys[key.column], key.constraint)
+	}
+	return foreignKeys, nil
+}
+
+func (d *sqliteDriver) getCreateSQL(tableName string) (string, errors.Error) {
+	var createSQL sql.NullString
+	err := d.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&createSQL)
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	return createSQL.String, nil
+}
+
+// getTriggers returns the CREATE TRIGGER statements of the triggers of a
+// table.
+func (d *sqliteDriver) getTriggers(tableName string) ([]string, errors.Error) {
+	rows, err := d.db.Query("SELECT sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY name", tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var triggers []string
+	for rows.Next() {
+		var createSQL string
+		if err := rows.Scan(&createSQL); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		triggers = append(triggers, createSQL)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return triggers, nil
+}
+
+// getIndexSQL returns the CREATE INDEX statement of an index.
+func (d *sqliteDriver) getIndexSQL(indexName string) (string, errors.Error) {
+	var createSQL sql.NullString
+	err := d.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", indexName).Scan(&createSQL)
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	return createSQL.String, nil
+}
+
+// sqliteView is a view as it is stored in sqlite_master.
+type sqliteView struct {
+	name      string
+	createSQL string
+}
+
+// getViews returns the views reading a table, directly or through other
+// views, in the order they were created. A view is taken as reading a table
+// when its statement mentions its name.
+func (d *sqliteDriver) getViews(tableName string) ([]sqliteView, errors.Error) {
+	rows, err := d.db.Query("SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY rowid")
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var views []sqliteView
+	for rows.Next() {
+		var view sqliteView
+		if err := rows.Scan(&view.name, &view.createSQL); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+
+	read := map[string]bool{strings.ToLower(tableName): true}
+	for found := true; found; {
+		found = false
+		for _, view := range views {
+			name := strings.ToLower(view.name)
+			if read[name] {
+				continue
+			}
+			for readName := range read {
+				if strings.Contains(strings.ToLower(view.createSQL), readName) {
+					read[name] = true
+					found = true
+					break
+				}
+			}
+		}
+	}
+	reading := make([]sqliteView, 0, len(read)-1)
+	for _, view := range views {
+		if read[strings.ToLower(view.name)] {
+			reading = append(reading, view)
+		}
+	}
+	return reading, nil
+}
+
+// sqliteColumnConstraints parses the AUTOINCREMENT and CHECK clauses
+// declared in the column definitions of a CREATE TABLE statement, by column
+// name. sqlite does not expose them in any pragma.
+func sqliteColumnConstraints(createSQL string) map[string][]schema.Constraint {
+	constraints := make(map[string][]schema.Constraint)
+	start := strings.Index(createSQL, "(")
+	end := strings.LastIndex(createSQL, ")")
+	if start == -1 || end <= start {
+		return constraints
+	}
+	for _, definition := range splitSQL(createSQL[start+1:end], ',') {
+		words := splitSQL(definition, ' ')
+		if len(words) == 0 {
+			continue
+		}
+		switch strings.ToUpper(words[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			continue
+		}
+		column := unquoteIdentifier(words[0])
+		for i, word := range words {
+			if strings.EqualFold(word, "AUTOINCREMENT") {
+				constraints[column] = append(constraints[column], schema.AutoIncrementConstraint{})
+				continue
+			}
+			expression, ok := checkClause(word)
+			if !ok && strings.EqualFold(word, "CHECK") && i+1 < len(words) {
+				expression, ok = checkClause("CHECK" + words[i+1])
+			}
+			if ok {
+				constraints[column] = append(constraints[column], schema.CheckConstraint{Expression: expression})
+			}
+		}
+	}
+	return constraints
+}
+
+// sqliteTableConstraints parses the constraints declared after the columns
+// of a CREATE TABLE statement. sqlite does not expose their names in any
+// pragma.
+func sqliteTableConstraints(createSQL string) []schema.TableConstraint {
+	var constraints []schema.TableConstraint
+	start := strings.Index(createSQL, "(")
+	end := strings.LastIndex(createSQL, ")")
+	if start == -1 || end <= start {
+		return constraints
+	}
+	for _, definition := range splitSQL(createSQL[start+1:end], ',') {
+		var constraint schema.TableConstraint
+		words := splitSQL(definition, ' ')
+		if len(words) > 2 && strings.EqualFold(words[0], "CONSTRAINT") {
+			constraint.Name = unquoteIdentifier(words[1])
+			definition = strings.Join(words[2:], " ")
+		}
+		inner, rest := parenthesized(definition)
+		keyword := strings.ToUpper(strings.Join(strings.Fields(definition[:max(strings.Index(definition, "("), 0)]), " "))
+		switch keyword {
+		case "PRIMARY KEY":
+			constraint.Type = schema.PrimaryKeyConstraintType
+			constraint.Columns = sqliteColumnList(inner)
+		case "UNIQUE":
+			constraint.Type = schema.UniqueConstraintType
+			constraint.Columns = sqliteColumnList(inner)
+		case "CHECK":
+			constraint.Type = schema.CheckConstraintType
+			constraint.Expression = strings.TrimSpace(inner)
+		case "FOREIGN KEY":
+			constraint.Type = schema.ForeignKeyConstraintType
+			constraint.Columns = sqliteColumnList(inner)
+			referenced, actions := parenthesized(rest)
+			words := strings.Fields(rest[:max(strings.Index(rest, "("), 0)])
+			if len(words) == 2 && strings.EqualFold(words[0], "REFERENCES") {
+				constraint.ReferencedTable = unquoteIdentifier(words[1])
+			}
+			constraint.ReferencedColumns = sqliteColumnList(referenced)
+			constraint.OnDelete, constraint.OnUpdate = sqliteReferentialActions(actions)
+		default:
+			continue
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
+// parenthesized returns the content of the first parentheses of s and what
+// follows them.
+func parenthesized(s string) (string, string) {
+	start := strings.Index(s, "(")
+	if start == -1 {
+		return "", s
+	}
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[start+1 : i], s[i+1:]
+			}
+		}
+	}
+	return s[start+1:], ""
+}
+
+func sqliteColumnList(list string) []string {
+	var columns []string
+	for _, column := range splitSQL(list, ',') {
+		columns = append(columns, unquoteIdentifier(splitSQL(column, ' ')[0]))
+	}
+	return columns
+}
+
+// sqliteReferentialActions parses the ON DELETE and ON UPDATE clauses of a
+// foreign key.
+func sqliteReferentialActions(clauses string) (string, string) {
+	var onDelete, onUpdate string
+	words := strings.Fields(strings.ToUpper(clauses))
+	for i := 0; i+2 < len(words); i++ {
+		if words[i] != "ON" {
+			continue
+		}
+		action := words[i+2]
+		if (action == "SET" || action == "NO") && i+3 < len(words) {
+			action += " " + words[i+3]
+		}
+		switch words[i+1] {
+		case "DELETE":
+			onDelete = referentialAction(action)
+		case "UPDATE":
+			onUpdate = referentialAction(action)
+		}
+	}
+	return onDelete, onUpdate
+}
+
+// sqliteIndexWhere returns the WHERE clause of a CREATE INDEX statement.
+func sqliteIndexWhere(createSQL string) string {
+	words := splitSQL(createSQL, ' ')
+	for i, word := range words {
+		if strings.EqualFold(word, "WHERE") {
+			return strings.Join(words[i+1:], " ")
+		}
+	}
+	return ""
+}
+
+// checkClause returns the expression of a CHECK(expr) word.
+func checkClause(word string) (string, bool) {
+	if len(word) < 5 || !strings.EqualFold(word[:5], "CHECK") {
+		return "", false
+	}
+	expression := strings.TrimSpace(word[5:])
+	if !strings.HasPrefix(expression, "(") || !strings.HasSuffix(expression, ")") {
+		return "", false
+	}
+	return strings.TrimSpace(expression[1 : len(expression)-1]), true
+}
+
+// splitSQL splits a piece of SQL on a separator, ignoring separators inside
+// parentheses, quoted identifiers and string literals. Empty parts are
+// dropped and the parts are trimmed.
+func splitSQL(s string, separator rune) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '[':
+			quote = ']'
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && (r == separator || separator == ' ' && unicode.IsSpace(r)):
+			if part := strings.TrimSpace(current.String()); part != "" {
+				parts = append(parts, part)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if part := strings.TrimSpace(current.String()); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func unquoteIdentifier(identifier string) string {
+	if len(identifier) < 2 {
+		return identifier
+	}
+	first, last := identifier[0], identifier[len(identifier)-1]
+	if first == '"' && last == '"' || first == '`' && last == '`' || first == '[' && last == ']' {
+		inner := identifier[1 : len(identifier)-1]
+		if first == '[' {
+			return inner
+		}
+		return strings.ReplaceAll(inner, string(first)+string(first), string(first))
+	}
+	return identifier
 }
 
-func (d *sqliteDriver) Version() float32 {
+func (d *sqliteDriver) Version() Version {
 	return d.version
 }
 
@@ -124,11 +708,14 @@ func (d *sqliteDriver) Close() errors.Error {
 }
 
 var sqliteDriverInstance = &sqliteDriver{
-	version: 3.35,
+	version: Version{Major: 3, Minor: 35, Patch: 0},
 	dataTypes: []schema.DataType{
 		"INTEGER",
 		"REAL",
+		"NUMERIC",
 		"TEXT",
+		"VARCHAR",
+		"CHAR",
 		"BLOB",
 		"NULL",
 		"DATE",
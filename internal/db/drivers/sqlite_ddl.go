@@ -0,0 +1,286 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func (d *sqliteDriver) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (d *sqliteDriver) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "\"")
+}
+
+func (d *sqliteDriver) QuoteLiteral(value string) string {
+	return quoteLiteral(value)
+}
+
+func (d *sqliteDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return sqliteTypeRules.resolveType(t)
+}
+
+func (d *sqliteDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return sqliteTypeRules.normalizeType(t)
+}
+
+// AutoIncrementClause is empty, sqlite only allows AUTOINCREMENT right after
+// the PRIMARY KEY of an INTEGER column, where it is rendered along with the
+// key.
+func (d *sqliteDriver) AutoIncrementClause() string {
+	return ""
+}
+
+func (d *sqliteDriver) PrimaryKeyClause(column schema.Column) string {
+	if schema.HasConstraint(column.Constraints, schema.AutoIncrementConstraint{}) {
+		return " PRIMARY KEY AUTOINCREMENT"
+	}
+	return " PRIMARY KEY"
+}
+
+func (d *sqliteDriver) UniqueConstraintName(table schema.Table, column schema.Column) string {
+	return constraintName(table, column, "key")
+}
+
+func (d *sqliteDriver) LargeTextType() schema.DataType {
+	return "TEXT"
+}
+
+func (d *sqliteDriver) columnDefinition(table schema.Table, column schema.Column) string {
+	return fmt.Sprintf("%s %s%s", d.QuoteIdentifier(column.Name), column.Type, inlineConstraints(d, table, column, true))
+}
+
+func (d *sqliteDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	return []string{createTable(d, table, d.columnDefinition)}, nil
+}
+
+func (d *sqliteDriver) DropTable(table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *sqliteDriver) RenameTable(previousName string, table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", d.QuoteIdentifier(previousName), d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *sqliteDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	definition := d.columnDefinition(table, column)
+	for _, constraint := range column.Constraints {
+		switch c := constraint.(type) {
+		case schema.PrimaryKeyConstraint, schema.UniqueConstraint:
+			return nil, errors.New(fmt.Sprintf("sqlite cannot add column %s.%s with constraint %s", table.Name, column.Name, c.Name()))
+		case schema.NotNullConstraint:
+			if _, ok := columnDefault(column); !ok {
+				return nil, errors.New(fmt.Sprintf("sqlite cannot add column %s.%s NOT NULL without a default", table.Name, column.Name))
+			}
+		case schema.ForeignKeyConstraint:
+			definition += " " + referencesClause(d, c)
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", d.QuoteIdentifier(table.Name), definition)}, nil
+}
+
+// The versions introducing DROP COLUMN and RENAME COLUMN.
+var (
+	sqliteDropColumnSince   = Version{Major: 3, Minor: 35, Patch: 0}
+	sqliteRenameColumnSince = Version{Major: 3, Minor: 25, Patch: 0}
+)
+
+func (d *sqliteDriver) DropColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	if !d.version.AtLeast(sqliteDropColumnSince) {
+		return nil, unsupportedFeature("sqlite", d.version, "DROP COLUMN", sqliteDropColumnSince)
+	}
+	for _, constraint := range column.Constraints {
+		switch c := constraint.(type) {
+		case schema.PrimaryKeyConstraint, schema.UniqueConstraint, schema.ForeignKeyConstraint, schema.CheckConstraint:
+			return nil, errors.New(fmt.Sprintf("sqlite cannot drop column %s.%s with constraint %s", table.Name, column.Name, c.Name()))
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(column.Name))}, nil
+}
+
+func (d *sqliteDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	if !d.version.AtLeast(sqliteRenameColumnSince) {
+		return nil, unsupportedFeature("sqlite", d.version, "RENAME COLUMN", sqliteRenameColumnSince)
+	}
+	return []string{renameColumn(d, table, previousName, column)}, nil
+}
+
+func (d *sqliteDriver) AlterColumnType(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return nil, errors.New(fmt.Sprintf("sqlite cannot alter the type of column %s.%s", table.Name, column.Name))
+}
+
+// AddConstraint only supports unique constraints, which sqlite can enforce
+// with a unique index. Other constraints can only be declared when the table
+// is created.
+func (d *sqliteDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	if _, ok := constraint.(schema.UniqueConstraint); ok {
+		return []string{fmt.Sprintf(
+			"CREATE UNIQUE INDEX %s ON %s (%s)",
+			d.QuoteIdentifier(constraintName(table, column, "key")),
+			d.QuoteIdentifier(table.Name),
+			d.QuoteIdentifier(column.Name),
+		)}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("sqlite cannot add constraint %s to existing column %s.%s", constraint.Name(), table.Name, column.Name))
+}
+
+func (d *sqliteDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	if _, ok := constraint.(schema.UniqueConstraint); ok {
+		return []string{fmt.Sprintf("DROP INDEX %s", d.QuoteIdentifier(constraintName(table, column, "key")))}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("sqlite cannot drop constraint %s from column %s.%s", constraint.Name(), table.Name, column.Name))
+}
+
+func (d *sqliteDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return nil, errors.New(fmt.Sprintf("sqlite cannot add constraint %s to existing table %s", constraint.Name, table.Name))
+}
+
+func (d *sqliteDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return nil, errors.New(fmt.Sprintf("sqlite cannot drop constraint %s from table %s", constraint.Name, table.Name))
+}
+
+func (d *sqliteDriver) CreateIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	if index.Method != "" {
+		return nil, errors.New(fmt.Sprintf("sqlite does not support index methods, index %s uses %s", index.Name, index.Method))
+	}
+	return []string{createIndex(d, table, index, "")}, nil
+}
+
+func (d *sqliteDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP INDEX %s", d.QuoteIdentifier(index.Name))}, nil
+}
+
+func (d *sqliteDriver) IndexNamesPerTable() bool {
+	return false
+}
+
+func (d *sqliteDriver) UniqueIndexesAsConstraints() bool {
+	return false
+}
+
+// RebuildTable follows the procedure of the sqlite documentation for the
+// schema changes ALTER TABLE cannot make. The table is created as
+// new_<name>, the rows are copied to it, the live table is dropped and the
+// new one takes its name. Indexes and triggers are dropped along with the
+// live table and created again, as are the views reading it, which would
+// prevent the renaming. When the driver is connected, triggers, views and
+// the live indexes missing from the desired table are read from the
+// database.
+func (d *sqliteDriver) RebuildTable(previousName string, table schema.Table, columns []string, droppedIndexes []string) ([]string, errors.Error) {
+	var kept, triggers []string
+	var views []sqliteView
+	if d.db != nil {
+		var err errors.Error
+		kept, err = d.keptIndexes(previousName, table, droppedIndexes)
+		if err != nil {
+			return nil, err
+		}
+		triggers, err = d.getTriggers(previousName)
+		if err != nil {
+			return nil, err
+		}
+		views, err = d.getViews(previousName)
+		if err != nil {
+			return nil, err
+		}
+		if len(kept)+len(triggers)+len(views) > 0 && previousName != table.Name {
+			return nil, errors.New(fmt.Sprintf("sqlite cannot rebuild table %s renamed from %s along with its indexes, triggers and views, rename it in a separate migration", table.Name, previousName))
+		}
+	}
+	var statements []string
+	for i := len(views) - 1; i >= 0; i-- {
+		statements = append(statements, fmt.Sprintf("DROP VIEW %s", d.QuoteIdentifier(views[i].name)))
+	}
+	// The constraints keep the names they get from the desired table, only
+	// the name of the created table changes.
+	newName := d.QuoteIdentifier("new_" + table.Name)
+	createSQL := createTable(d, table, d.columnDefinition)
+	statements = append(statements, strings.Replace(createSQL, d.QuoteIdentifier(table.Name), newName, 1))
+	if len(columns) > 0 {
+		quoted := quoteIdentifiers(d, columns)
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", newName, quoted, quoted, d.QuoteIdentifier(table.Name)))
+	}
+	statements = append(statements,
+		fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", newName, d.QuoteIdentifier(table.Name)),
+	)
+	for _, index := range table.Indexes {
+		sql, err := d.CreateIndex(table, index)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, sql...)
+	}
+	statements = append(statements, kept...)
+	statements = append(statements, triggers...)
+	for _, view := range views {
+		statements = append(statements, view.createSQL)
+	}
+	return statements, nil
+}
+
+// keptIndexes returns the CREATE INDEX statements of the live indexes of a
+// table which are neither desired nor dropped.
+func (d *sqliteDriver) keptIndexes(tableName string, table schema.Table, droppedIndexes []string) ([]string, errors.Error) {
+	indexes, _, err := d.getIndexes(tableName)
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[string]bool, len(table.Indexes)+len(droppedIndexes))
+	for _, index := range table.Indexes {
+		skipped[index.Name] = true
+	}
+	for _, name := range droppedIndexes {
+		skipped[name] = true
+	}
+	var kept []string
+	for _, index := range indexes {
+		if skipped[index.Name] {
+			continue
+		}
+		createSQL, err := d.getIndexSQL(index.Name)
+		if err != nil {
+			return nil, err
+		}
+		kept = append(kept, createSQL)
+	}
+	return kept, nil
+}
+
+func (d *sqliteDriver) ForeignKeyCheck(table schema.Table) string {
+	return fmt.Sprintf("PRAGMA foreign_key_check(%s)", d.QuoteIdentifier(table.Name))
+}
+
+// ForeignKeys toggles the enforcement of foreign keys, which sqlite ignores
+// within a transaction.
+func (d *sqliteDriver) ForeignKeys(enabled bool) string {
+	if enabled {
+		return "PRAGMA foreign_keys = ON"
+	}
+	return "PRAGMA foreign_keys = OFF"
+}
+
+// sqliteLogicalTypes maps logical types to sqlite types. Integers are all
+// 64 bits wide in sqlite and INTEGER keeps primary keys aliases of the
+// rowid.
+var sqliteLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INTEGER"},
+	schema.Int64Type:     {plain: "INTEGER"},
+	schema.StringType:    {plain: "TEXT", parameterized: "VARCHAR(%s)"},
+	schema.DecimalType:   {plain: "NUMERIC", parameterized: "NUMERIC(%s)"},
+	schema.BoolType:      {plain: "BOOLEAN"},
+	schema.TimestampType: {plain: "DATETIME"},
+	schema.UUIDType:      {plain: "TEXT"},
+	schema.JSONType:      {plain: "TEXT"},
+	schema.BytesType:     {plain: "BLOB"},
+}
+
+// sqliteTypeRules has no aliases: sqlite keeps the declared type, and INT
+// and INTEGER primary keys do not behave the same.
+var sqliteTypeRules = typeRules{
+	logical: sqliteLogicalTypes,
+}
@@ -0,0 +1,222 @@
+package drivers
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestSqliteGetTable(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	d := &sqliteDriver{db: db}
+
+	users := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+			{Name: "email", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.UniqueConstraint{}}},
+			{Name: "status", Type: "TEXT", Constraints: schema.Constraints{
+				schema.DefaultConstraint{Value: "'active'"},
+				schema.CheckConstraint{Expression: "status IN ('active', 'disabled')"},
+			}},
+		},
+	}
+	posts := schema.Table{
+		Name: "posts",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+			{Name: "user_id", Type: "INTEGER", Constraints: schema.Constraints{
+				schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"},
+			}},
+			{Name: "slug", Type: "TEXT"},
+		},
+	}
+	for _, table := range []schema.Table{users, posts} {
+		statements, err := d.CreateTable(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatalf("%s: %s", statement, err)
+			}
+		}
+	}
+	if _, err := db.Exec(`CREATE UNIQUE INDEX "posts_slug_key" ON "posts" ("slug")`); err != nil {
+		t.Fatal(err)
+	}
+	posts.Columns[2].Constraints = schema.Constraints{schema.UniqueConstraint{}}
+	posts.Indexes = []schema.Index{
+		{Name: "posts_user_id_slug_idx", Columns: []string{"user_id", "slug"}, Unique: true, Where: "slug IS NOT NULL"},
+	}
+	statements, err := d.CreateIndex(posts, posts.Indexes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(statements[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	postTags := schema.Table{
+		Name: "post_tags",
+		Columns: []schema.Column{
+			{Name: "post_id", Type: "INTEGER"},
+			{Name: "user_id", Type: "INTEGER"},
+			{Name: "tag", Type: "TEXT"},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "post_tags_pkey", Type: schema.PrimaryKeyConstraintType, Columns: []string{"post_id", "tag"}},
+			{Name: "post_tags_post_fkey", Type: schema.ForeignKeyConstraintType, Columns: []string{"post_id", "user_id"}, ReferencedTable: "posts", ReferencedColumns: []string{"id", "user_id"}, OnDelete: "SET NULL"},
+			{Name: "post_tags_tag_unique", Type: schema.UniqueConstraintType, Columns: []string{"tag"}},
+			{Name: "post_tags_tag_check", Type: schema.CheckConstraintType, Expression: "length(tag) > 0"},
+		},
+	}
+	statements, err = d.CreateTable(postTags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(statements[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []schema.Table{users, posts, postTags} {
+		got, err := d.GetTable(want.Name)
+		if err != nil {
+			t.Fatalf("GetTable(%q) error = %v", want.Name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetTable(%q) = %+v, want %+v", want.Name, got, want)
+		}
+	}
+}
+
+func TestSqliteColumnConstraints(t *testing.T) {
+	createSQL := `CREATE TABLE "products" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "price" REAL NOT NULL CONSTRAINT "products_price_check" CHECK (price > 0),
+  [name] TEXT DEFAULT 'a, (b)' CHECK(length(name) < 10),
+  CHECK (price < 1000)
+)`
+	want := map[string][]schema.Constraint{
+		"id":    {schema.AutoIncrementConstraint{}},
+		"price": {schema.CheckConstraint{Expression: "price > 0"}},
+		"name":  {schema.CheckConstraint{Expression: "length(name) < 10"}},
+	}
+	got := sqliteColumnConstraints(createSQL)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sqliteColumnConstraints() = %v, want %v", got, want)
+	}
+}
+
+func TestSqliteRebuildTable(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	d := &sqliteDriver{version: Version{Major: 3, Minor: 35, Patch: 0}, db: db}
+
+	for _, statement := range []string{
+		`CREATE TABLE "users" ("id" INTEGER PRIMARY KEY, "age" TEXT, "email" TEXT)`,
+		`CREATE INDEX "users_email_idx" ON "users" ("email")`,
+		`CREATE INDEX "users_id_email_idx" ON "users" ("id", "email")`,
+		`CREATE INDEX "users_age_idx" ON "users" ("age")`,
+		`CREATE VIEW "adults" AS SELECT * FROM "users" WHERE "age" >= 18`,
+		`CREATE VIEW "adult_emails" AS SELECT "email" FROM "adults"`,
+		`CREATE TABLE "posts" ("id" INTEGER PRIMARY KEY, "user_id" INTEGER REFERENCES "users" ("id") ON DELETE CASCADE)`,
+		`CREATE TABLE "audit" ("user_id" INTEGER)`,
+		`CREATE TRIGGER "users_audit" AFTER UPDATE ON "users" BEGIN INSERT INTO "audit" VALUES (new.id); END`,
+		`INSERT INTO "users" VALUES (1, '42', 'a@example.com')`,
+		`INSERT INTO "posts" VALUES (1, 1)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %s", statement, err)
+		}
+	}
+
+	users := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER", Constraints: schema.Constraints{schema.PrimaryKeyConstraint{}}},
+			{Name: "age", Type: "INTEGER", Constraints: schema.Constraints{schema.CheckConstraint{Expression: "age >= 0"}}},
+			{Name: "email", Type: "TEXT"},
+			{Name: "active", Type: "BOOLEAN", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.DefaultConstraint{Value: "1"}}},
+		},
+		Indexes: []schema.Index{{Name: "users_email_idx", Columns: []string{"email"}}},
+	}
+	statements, err := d.RebuildTable("users", users, []string{"id", "age", "email"}, []string{"users_age_idx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(d.ForeignKeys(false)); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			t.Fatalf("%s: %s", statement, err)
+		}
+	}
+	rows, err := tx.Query(d.ForeignKeyCheck(users))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() {
+		t.Error("foreign key check returned violations")
+	}
+	rows.Close()
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(d.ForeignKeys(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.GetTable("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := users
+	want.Indexes = append(want.Indexes, schema.Index{Name: "users_id_email_idx", Columns: []string{"id", "email"}})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTable() = %+v, want %+v", got, want)
+	}
+	var age, posts, triggers int
+	var active bool
+	if err := db.QueryRow(`SELECT "age", "active" FROM "users" WHERE "id" = 1`).Scan(&age, &active); err != nil {
+		t.Fatal(err)
+	}
+	if age != 42 || !active {
+		t.Errorf("rebuilt row = (%d, %t), want (42, true)", age, active)
+	}
+	if err := db.QueryRow(`SELECT count(*) FROM "posts"`).Scan(&posts); err != nil {
+		t.Fatal(err)
+	}
+	if posts != 1 {
+		t.Errorf("posts = %d, want the rows referencing the rebuilt table kept", posts)
+	}
+	if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND tbl_name = 'users'`).Scan(&triggers); err != nil {
+		t.Fatal(err)
+	}
+	if triggers != 1 {
+		t.Errorf("triggers = %d, want the trigger of the table recreated", triggers)
+	}
+	var email string
+	if err := db.QueryRow(`SELECT "email" FROM "adult_emails"`).Scan(&email); err != nil {
+		t.Fatalf("views reading the table not recreated: %s", err)
+	}
+	if email != "a@example.com" {
+		t.Errorf("adult_emails = %q, want a@example.com", email)
+	}
+}
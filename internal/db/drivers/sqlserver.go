@@ -0,0 +1,400 @@
+package drivers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+	"github.com/yassirdeveloper/migrater/internal/utils"
+)
+
+type sqlserverDriver struct {
+	version   Version
+	dataTypes []schema.DataType
+	db        *sql.DB
+	tx        *sql.Tx
+	*mssql.Driver
+}
+
+func (d *sqlserverDriver) GetDataTypes() []schema.DataType {
+	return d.dataTypes
+}
+
+func (d *sqlserverDriver) Connect(dsn utils.DSN) errors.Error {
+	if d.db != nil && d.db.Ping() == nil {
+		return errors.New("connection already exists")
+	}
+	db, err := sql.Open("sqlserver", dsn.String())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Could not establish connection!\n%s", err))
+	}
+	version, err_ := serverVersion(db, "SELECT CAST(SERVERPROPERTY('ProductVersion') AS NVARCHAR(128))")
+	if err_ != nil {
+		db.Close()
+		return err_
+	}
+	d.db = db
+	d.version = version
+	return nil
+}
+
+func (d *sqlserverDriver) Execute(query string) errors.Error {
+	var err error
+	if d.tx != nil {
+		_, err = d.tx.Exec(query)
+	} else {
+		_, err = d.db.Exec(query)
+	}
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *sqlserverDriver) Query(query string) (Result, errors.Error) {
+	var rows *sql.Rows
+	var err error
+	if d.tx != nil {
+		rows, err = d.tx.Query(query)
+	} else {
+		rows, err = d.db.Query(query)
+	}
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return rows, nil
+}
+
+func (d *sqlserverDriver) Begin() errors.Error {
+	if d.tx != nil {
+		return errors.New("transaction already started")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	d.tx = tx
+	return nil
+}
+
+func (d *sqlserverDriver) Commit() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to commit")
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func (d *sqlserverDriver) Rollback() errors.Error {
+	if d.tx == nil {
+		return errors.New("no transaction to rollback")
+	}
+	err := d.tx.Rollback()
+	d.tx = nil
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+// GetTableNames returns the user tables of the default schema of the
+// connected user.
+func (d *sqlserverDriver) GetTableNames() ([]string, errors.Error) {
+	query := "SELECT name FROM sys.tables WHERE schema_id = SCHEMA_ID() AND is_ms_shipped = 0 ORDER BY name"
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+
+	return tableNames, nil
+}
+
+func (d *sqlserverDriver) GetTable(tableName string) (schema.Table, errors.Error) {
+	columns, err := d.getColumns(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	keyConstraints, err := d.getKeyConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	checkConstraints, err := d.getCheckConstraints(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	indexes, err := d.getIndexes(tableName)
+	if err != nil {
+		return schema.Table{}, err
+	}
+	columnConstraints, tableConstraints := splitConstraints(d, tableName, append(keyConstraints, checkConstraints...))
+	for i := range columns {
+		columns[i].Constraints = append(columns[i].Constraints, columnConstraints[columns[i].Name]...)
+	}
+	return schema.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Constraints: tableConstraints,
+		Indexes:     indexes,
+	}, nil
+}
+
+func (d *sqlserverDriver) getColumns(tableName string) ([]schema.Column, errors.Error) {
+	query := `SELECT c.name, t.name, c.max_length, c.precision, c.scale, c.is_nullable, c.is_identity, dc.definition
+	FROM sys.columns c
+	JOIN sys.types t ON t.user_type_id = c.user_type_id
+	LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+	WHERE c.object_id = OBJECT_ID(@p1)
+	ORDER BY c.column_id`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var columns []schema.Column
+	for rows.Next() {
+		var column schema.Column
+		var typeName string
+		var maxLength, precision, scale int64
+		var isNullable, isIdentity bool
+		var columnDefault sql.NullString
+		if err := rows.Scan(&column.Name, &typeName, &maxLength, &precision, &scale, &isNullable, &isIdentity, &columnDefault); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		column.Type = sqlserverColumnType(typeName, maxLength, precision, scale)
+		if !isNullable {
+			column.Constraints = append(column.Constraints, schema.NotNullConstraint{})
+		}
+		if columnDefault.Valid {
+			column.Constraints = append(column.Constraints, schema.DefaultConstraint{Value: sqlserverExpression(columnDefault.String)})
+		}
+		if isIdentity {
+			column.Constraints = append(column.Constraints, schema.AutoIncrementConstraint{})
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return columns, nil
+}
+
+// getKeyConstraints returns the primary key, unique and foreign key
+// constraints of a table.
+func (d *sqlserverDriver) getKeyConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME,
+		COALESCE(rkcu.TABLE_NAME, ''), COALESCE(rkcu.COLUMN_NAME, ''),
+		COALESCE(rc.UPDATE_RULE, ''), COALESCE(rc.DELETE_RULE, '')
+	FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+	JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+		ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		AND kcu.TABLE_NAME = tc.TABLE_NAME
+	LEFT JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+		ON rc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND rc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+	LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE rkcu
+		ON rkcu.CONSTRAINT_SCHEMA = rc.UNIQUE_CONSTRAINT_SCHEMA AND rkcu.CONSTRAINT_NAME = rc.UNIQUE_CONSTRAINT_NAME
+		AND rkcu.ORDINAL_POSITION = kcu.ORDINAL_POSITION
+	WHERE tc.TABLE_SCHEMA = SCHEMA_NAME() AND tc.TABLE_NAME = @p1
+		AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+	ORDER BY tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		var name, constraintType, column, referencedTable, referencedColumn, updateRule, deleteRule string
+		if err := rows.Scan(&name, &constraintType, &column, &referencedTable, &referencedColumn, &updateRule, &deleteRule); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraints = appendKeyColumn(constraints, schema.TableConstraint{
+			Name:            name,
+			Type:            keyConstraintType(constraintType),
+			ReferencedTable: referencedTable,
+			OnDelete:        referentialAction(deleteRule),
+			OnUpdate:        referentialAction(updateRule),
+		}, column, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// getCheckConstraints returns the check constraints of a table. A check
+// declared on a column records it as its parent column.
+func (d *sqlserverDriver) getCheckConstraints(tableName string) ([]schema.TableConstraint, errors.Error) {
+	query := `SELECT cc.name, cc.definition, COALESCE(COL_NAME(cc.parent_object_id, cc.parent_column_id), '')
+	FROM sys.check_constraints cc
+	WHERE cc.parent_object_id = OBJECT_ID(@p1)
+	ORDER BY cc.name`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var constraints []schema.TableConstraint
+	for rows.Next() {
+		constraint := schema.TableConstraint{Type: schema.CheckConstraintType}
+		var definition, column string
+		if err := rows.Scan(&constraint.Name, &definition, &column); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		constraint.Expression = sqlserverExpression(definition)
+		if column != "" {
+			constraint.Columns = []string{column}
+		}
+		constraints = append(constraints, constraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return constraints, nil
+}
+
+// getIndexes returns the indexes of a table that do not back a primary key
+// or unique constraint. Nonclustered being the default, only clustered
+// indexes have a method.
+func (d *sqlserverDriver) getIndexes(tableName string) ([]schema.Index, errors.Error) {
+	query := `SELECT i.name, i.is_unique, i.type_desc, COALESCE(i.filter_definition, ''), c.name
+	FROM sys.indexes i
+	JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+	WHERE i.object_id = OBJECT_ID(@p1) AND i.is_primary_key = 0 AND i.is_unique_constraint = 0
+		AND i.type > 0 AND ic.key_ordinal > 0
+	ORDER BY i.name, ic.key_ordinal`
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	defer rows.Close()
+
+	var indexes []schema.Index
+	for rows.Next() {
+		var name, indexType, where, column string
+		var unique bool
+		if err := rows.Scan(&name, &unique, &indexType, &where, &column); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			index := schema.Index{Name: name, Unique: unique, Where: sqlserverExpression(where)}
+			if indexType != "NONCLUSTERED" {
+				index.Method = strings.ToLower(indexType)
+			}
+			indexes = append(indexes, index)
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return indexes, nil
+}
+
+// sqlserverColumnType converts a sys.types name and the sizes of a column to
+// the type used in a column definition. max_length is in bytes, -1 standing
+// for MAX, and is twice the length of unicode types.
+func sqlserverColumnType(typeName string, maxLength, precision, scale int64) schema.DataType {
+	columnType := strings.ToUpper(typeName)
+	switch columnType {
+	case "CHAR", "VARCHAR", "BINARY", "VARBINARY", "NCHAR", "NVARCHAR":
+		if maxLength == -1 {
+			return schema.DataType(columnType + "(MAX)")
+		}
+		if strings.HasPrefix(columnType, "N") {
+			maxLength /= 2
+		}
+		return schema.DataType(fmt.Sprintf("%s(%d)", columnType, maxLength))
+	case "DECIMAL", "NUMERIC":
+		return schema.DataType(fmt.Sprintf("%s(%d,%d)", columnType, precision, scale))
+	case "DATETIME2", "DATETIMEOFFSET", "TIME":
+		if scale != 7 {
+			return schema.DataType(fmt.Sprintf("%s(%d)", columnType, scale))
+		}
+	}
+	return schema.DataType(columnType)
+}
+
+// sqlserverExpression converts an expression as stored by sql server, e.g.
+// ([price]>(0)), back to an expression without bracket quoting nor
+// surrounding parentheses.
+func sqlserverExpression(definition string) string {
+	return unwrapParentheses(strings.NewReplacer("[", "", "]", "").Replace(definition))
+}
+
+func (d *sqlserverDriver) Version() Version {
+	return d.version
+}
+
+func (d *sqlserverDriver) Close() errors.Error {
+	if d.db != nil {
+		err := d.db.Close()
+		if err != nil {
+			return errors.NewUnexpectedError(err)
+		}
+		return nil
+	}
+	return nil
+}
+
+var sqlserverDriverInstance = &sqlserverDriver{
+	version: Version{Major: 15, Minor: 0, Patch: 0},
+	dataTypes: []schema.DataType{
+		"BIT",
+		"TINYINT",
+		"SMALLINT",
+		"INT",
+		"BIGINT",
+		"DECIMAL",
+		"NUMERIC",
+		"SMALLMONEY",
+		"MONEY",
+		"REAL",
+		"FLOAT",
+		"CHAR",
+		"VARCHAR",
+		"TEXT",
+		"NCHAR",
+		"NVARCHAR",
+		"NTEXT",
+		"BINARY",
+		"VARBINARY",
+		"IMAGE",
+		"DATE",
+		"TIME",
+		"SMALLDATETIME",
+		"DATETIME",
+		"DATETIME2",
+		"DATETIMEOFFSET",
+		"ROWVERSION",
+		"UNIQUEIDENTIFIER",
+		"XML",
+		"SQL_VARIANT",
+		"HIERARCHYID",
+		"GEOMETRY",
+		"GEOGRAPHY",
+	},
+}
@@ -0,0 +1,275 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func (d *sqlserverDriver) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// QuoteIdentifier quotes with brackets, only the closing bracket needs to be
+// escaped.
+func (d *sqlserverDriver) QuoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+func (d *sqlserverDriver) QuoteLiteral(value string) string {
+	return quoteLiteral(value)
+}
+
+func (d *sqlserverDriver) ResolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	return sqlserverTypeRules.resolveType(t)
+}
+
+func (d *sqlserverDriver) NormalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	return sqlserverTypeRules.normalizeType(t)
+}
+
+func (d *sqlserverDriver) AutoIncrementClause() string {
+	return " IDENTITY(1,1)"
+}
+
+func (d *sqlserverDriver) PrimaryKeyClause(column schema.Column) string {
+	return " PRIMARY KEY"
+}
+
+func (d *sqlserverDriver) UniqueConstraintName(table schema.Table, column schema.Column) string {
+	return constraintName(table, column, "key")
+}
+
+func (d *sqlserverDriver) LargeTextType() schema.DataType {
+	return "NVARCHAR(MAX)"
+}
+
+// columnDefinition renders a column for CREATE TABLE and ADD, with the
+// identity before the constraints as sql server documents it. Unnamed
+// constraints get a generated name, defaults and keys are named after the
+// constraint naming convention so that they can be dropped.
+func (d *sqlserverDriver) columnDefinition(table schema.Table, column schema.Column) string {
+	properties := ""
+	constraints := ""
+	for _, constraint := range column.GetConstraints() {
+		switch c := constraint.(type) {
+		case schema.NotNullConstraint:
+			properties += " NOT NULL"
+		case schema.DefaultConstraint:
+			properties += fmt.Sprintf(" CONSTRAINT %s DEFAULT %s", d.QuoteIdentifier(constraintName(table, column, "default")), c.Value)
+		case schema.AutoIncrementConstraint:
+			properties += d.AutoIncrementClause()
+		case schema.PrimaryKeyConstraint:
+			constraints += fmt.Sprintf(" CONSTRAINT %s PRIMARY KEY", d.QuoteIdentifier(table.Name+"_pkey"))
+		case schema.UniqueConstraint:
+			constraints += fmt.Sprintf(" CONSTRAINT %s UNIQUE", d.QuoteIdentifier(constraintName(table, column, "key")))
+		case schema.CheckConstraint:
+			constraints += fmt.Sprintf(" CONSTRAINT %s CHECK (%s)", d.QuoteIdentifier(constraintName(table, column, "check")), c.Expression)
+		}
+	}
+	return fmt.Sprintf("%s %s%s%s", d.QuoteIdentifier(column.Name), column.Type, properties, constraints)
+}
+
+func (d *sqlserverDriver) CreateTable(table schema.Table) ([]string, errors.Error) {
+	return []string{createTable(d, table, d.columnDefinition)}, nil
+}
+
+func (d *sqlserverDriver) DropTable(table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP TABLE %s", d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *sqlserverDriver) RenameTable(previousName string, table schema.Table) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("EXEC sp_rename %s, %s", d.QuoteLiteral(d.QuoteIdentifier(previousName)), d.QuoteLiteral(table.Name))}, nil
+}
+
+func (d *sqlserverDriver) AddColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	definition := d.columnDefinition(table, column)
+	for _, constraint := range column.Constraints {
+		if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+			definition += fmt.Sprintf(" CONSTRAINT %s %s", d.QuoteIdentifier(constraintName(table, column, "fkey")), referencesClause(d, fk))
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", d.QuoteIdentifier(table.Name), definition)}, nil
+}
+
+// DropColumn first drops the named constraints of the column, which sql
+// server refuses to drop along with it.
+func (d *sqlserverDriver) DropColumn(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	var statements []string
+	for _, constraint := range column.GetConstraints() {
+		switch constraint.(type) {
+		case schema.DefaultConstraint, schema.UniqueConstraint, schema.CheckConstraint, schema.ForeignKeyConstraint:
+			dropped, err := d.DropConstraint(table, column, constraint)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, dropped...)
+		}
+	}
+	return append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(column.Name))), nil
+}
+
+func (d *sqlserverDriver) RenameColumn(table schema.Table, previousName string, column schema.Column) ([]string, errors.Error) {
+	previous := d.QuoteIdentifier(table.Name) + "." + d.QuoteIdentifier(previousName)
+	return []string{fmt.Sprintf("EXEC sp_rename %s, %s, 'COLUMN'", d.QuoteLiteral(previous), d.QuoteLiteral(column.Name))}, nil
+}
+
+func (d *sqlserverDriver) AlterColumnType(table schema.Table, column schema.Column) ([]string, errors.Error) {
+	return []string{d.alterColumn(table, column, schema.HasConstraint(column.Constraints, schema.NotNullConstraint{}))}, nil
+}
+
+// alterColumn renders an ALTER COLUMN statement, which restates the type of
+// the column and its nullability, the column becoming nullable otherwise.
+func (d *sqlserverDriver) alterColumn(table schema.Table, column schema.Column, notNull bool) string {
+	nullability := "NULL"
+	if notNull {
+		nullability = "NOT NULL"
+	}
+	return fmt.Sprintf(
+		"ALTER TABLE %s ALTER COLUMN %s %s %s",
+		d.QuoteIdentifier(table.Name),
+		d.QuoteIdentifier(column.Name),
+		column.Type,
+		nullability,
+	)
+}
+
+func (d *sqlserverDriver) AddConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	columnName := d.QuoteIdentifier(column.Name)
+	var sql string
+	switch c := constraint.(type) {
+	case schema.NotNullConstraint:
+		sql = d.alterColumn(table, column, true)
+	case schema.AutoIncrementConstraint:
+		return nil, errors.New(fmt.Sprintf("sql server cannot add an identity to the existing column %s.%s", table.Name, column.Name))
+	case schema.DefaultConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s DEFAULT %s FOR %s", tableName, d.QuoteIdentifier(constraintName(table, column, "default")), c.Value, columnName)
+	case schema.PrimaryKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)", tableName, d.QuoteIdentifier(table.Name+"_pkey"), columnName)
+	case schema.UniqueConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", tableName, d.QuoteIdentifier(constraintName(table, column, "key")), columnName)
+	case schema.CheckConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)", tableName, d.QuoteIdentifier(constraintName(table, column, "check")), c.Expression)
+	case schema.ForeignKeyConstraint:
+		sql = fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, foreignKeyClause(d, table, column, c))
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{sql}, nil
+}
+
+func (d *sqlserverDriver) DropConstraint(table schema.Table, column schema.Column, constraint schema.Constraint) ([]string, errors.Error) {
+	tableName := d.QuoteIdentifier(table.Name)
+	var name string
+	switch constraint.(type) {
+	case schema.NotNullConstraint:
+		return []string{d.alterColumn(table, column, false)}, nil
+	case schema.AutoIncrementConstraint:
+		return nil, errors.New(fmt.Sprintf("sql server cannot remove the identity of the column %s.%s", table.Name, column.Name))
+	case schema.DefaultConstraint:
+		name = constraintName(table, column, "default")
+	case schema.PrimaryKeyConstraint:
+		name = table.Name + "_pkey"
+	case schema.UniqueConstraint:
+		name = constraintName(table, column, "key")
+	case schema.CheckConstraint:
+		name = constraintName(table, column, "check")
+	case schema.ForeignKeyConstraint:
+		name = constraintName(table, column, "fkey")
+	default:
+		return nil, unsupportedConstraint(table, column, constraint)
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, d.QuoteIdentifier(name))}, nil
+}
+
+func (d *sqlserverDriver) AddTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", d.QuoteIdentifier(table.Name), tableConstraintClause(d, constraint))}, nil
+}
+
+func (d *sqlserverDriver) DropTableConstraint(table schema.Table, constraint schema.TableConstraint) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", d.QuoteIdentifier(table.Name), d.QuoteIdentifier(constraint.Name))}, nil
+}
+
+// CreateIndex renders the clustered and nonclustered methods before the
+// INDEX keyword, filtered indexes with their WHERE clause.
+func (d *sqlserverDriver) CreateIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	method := strings.ToUpper(index.Method)
+	switch method {
+	case "":
+		return []string{createIndex(d, table, index, "")}, nil
+	case "CLUSTERED", "NONCLUSTERED":
+		return []string{strings.Replace(createIndex(d, table, index, ""), "INDEX ", method+" INDEX ", 1)}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("sql server does not support the %s index method of index %s", index.Method, index.Name))
+	}
+}
+
+func (d *sqlserverDriver) DropIndex(table schema.Table, index schema.Index) ([]string, errors.Error) {
+	return []string{fmt.Sprintf("DROP INDEX %s ON %s", d.QuoteIdentifier(index.Name), d.QuoteIdentifier(table.Name))}, nil
+}
+
+func (d *sqlserverDriver) IndexNamesPerTable() bool {
+	return true
+}
+
+func (d *sqlserverDriver) UniqueIndexesAsConstraints() bool {
+	return false
+}
+
+// sqlserverLogicalTypes maps logical types to sql server types, strings
+// being stored as unicode.
+var sqlserverLogicalTypes = map[schema.LogicalType]typeMapping{
+	schema.Int32Type:     {plain: "INT"},
+	schema.Int64Type:     {plain: "BIGINT"},
+	schema.StringType:    {plain: "NVARCHAR(MAX)", parameterized: "NVARCHAR(%s)"},
+	schema.DecimalType:   {plain: "DECIMAL", parameterized: "DECIMAL(%s)"},
+	schema.BoolType:      {plain: "BIT"},
+	schema.TimestampType: {plain: "DATETIME2"},
+	schema.UUIDType:      {plain: "UNIQUEIDENTIFIER"},
+	schema.JSONType:      {plain: "NVARCHAR(MAX)"},
+	schema.BytesType:     {plain: "VARBINARY(MAX)"},
+}
+
+var sqlserverTypeRules = typeRules{
+	logical: sqlserverLogicalTypes,
+	aliases: map[string]schema.DataType{
+		"INTEGER":                    "INT",
+		"DEC":                        "DECIMAL",
+		"DOUBLE PRECISION":           "FLOAT",
+		"CHARACTER":                  "CHAR",
+		"CHARACTER VARYING":          "VARCHAR",
+		"CHAR VARYING":               "VARCHAR",
+		"NATIONAL CHARACTER":         "NCHAR",
+		"NATIONAL CHAR":              "NCHAR",
+		"NATIONAL CHARACTER VARYING": "NVARCHAR",
+		"NATIONAL CHAR VARYING":      "NVARCHAR",
+		"BINARY VARYING":             "VARBINARY",
+	},
+	normalize: sqlserverDefaultSizes,
+}
+
+// sqlserverDefaultSizes writes sizes as sql server reports them: decimals
+// without a precision are DECIMAL(18,0), MAX is uppercase and the default
+// fractional second precision of 7 is left out.
+func sqlserverDefaultSizes(parsed schema.ParsedType) schema.ParsedType {
+	switch parsed.Base {
+	case "CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "BINARY", "VARBINARY":
+		if len(parsed.Arguments) == 1 && strings.EqualFold(parsed.Arguments[0], "MAX") {
+			parsed.Arguments = []string{"MAX"}
+		}
+	case "DECIMAL", "NUMERIC":
+		if len(parsed.Arguments) == 0 {
+			parsed.Arguments = []string{"18", "0"}
+		} else if len(parsed.Arguments) == 1 {
+			parsed.Arguments = []string{parsed.Arguments[0], "0"}
+		}
+	case "DATETIME2", "DATETIMEOFFSET", "TIME":
+		if len(parsed.Arguments) == 1 && parsed.Arguments[0] == "7" {
+			parsed.Arguments = nil
+		}
+	}
+	return parsed
+}
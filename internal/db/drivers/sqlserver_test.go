@@ -0,0 +1,99 @@
+package drivers
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestSqlserverGetTable(t *testing.T) {
+	db := sql.OpenDB(fakeDatabase{
+		"FROM sys.columns": {
+			{"id", "int", int64(4), int64(10), int64(0), false, true, nil},
+			{"email", "nvarchar", int64(510), int64(0), int64(0), false, false, nil},
+			{"status", "varchar", int64(16), int64(0), int64(0), true, false, "('active')"},
+			{"price", "decimal", int64(9), int64(10), int64(2), true, false, "((0))"},
+			{"org_id", "int", int64(4), int64(10), int64(0), true, false, nil},
+			{"created_at", "datetime2", int64(8), int64(27), int64(7), true, false, "(sysutcdatetime())"},
+		},
+		"FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS": {
+			{"PK__users__3213E83F", "PRIMARY KEY", "id", "", "", "", ""},
+			{"users_email_key", "UNIQUE", "email", "", "", "", ""},
+			{"users_email_org_id_key", "UNIQUE", "email", "", "", "", ""},
+			{"users_email_org_id_key", "UNIQUE", "org_id", "", "", "", ""},
+			{"users_org_id_fkey", "FOREIGN KEY", "org_id", "orgs", "id", "NO ACTION", "CASCADE"},
+		},
+		"FROM sys.check_constraints": {
+			{"users_check", "([price]>=(0) AND [status]<>'')", ""},
+			{"users_status_check", "([status]='active' OR [status]='disabled')", "status"},
+		},
+		"FROM sys.indexes": {
+			{"users_created_at_idx", false, "NONCLUSTERED", "([created_at] IS NOT NULL)", "created_at"},
+			{"users_status_price_idx", true, "NONCLUSTERED", "", "status"},
+			{"users_status_price_idx", true, "NONCLUSTERED", "", "price"},
+		},
+	})
+	defer db.Close()
+	d := &sqlserverDriver{db: db}
+
+	expected := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INT", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.AutoIncrementConstraint{}, schema.PrimaryKeyConstraint{}}},
+			{Name: "email", Type: "NVARCHAR(255)", Constraints: schema.Constraints{schema.NotNullConstraint{}, schema.UniqueConstraint{}}},
+			{Name: "status", Type: "VARCHAR(16)", Constraints: schema.Constraints{
+				schema.DefaultConstraint{Value: "'active'"},
+				schema.CheckConstraint{Expression: "status='active' OR status='disabled'"},
+			}},
+			{Name: "price", Type: "DECIMAL(10,2)", Constraints: schema.Constraints{schema.DefaultConstraint{Value: "0"}}},
+			{Name: "org_id", Type: "INT", Constraints: schema.Constraints{
+				schema.ForeignKeyConstraint{ReferencedTable: "orgs", ReferencedColumn: "id", OnDelete: "CASCADE"},
+			}},
+			{Name: "created_at", Type: "DATETIME2", Constraints: schema.Constraints{schema.DefaultConstraint{Value: "sysutcdatetime()"}}},
+		},
+		Constraints: []schema.TableConstraint{
+			{Name: "users_email_org_id_key", Type: schema.UniqueConstraintType, Columns: []string{"email", "org_id"}},
+			{Name: "users_check", Type: schema.CheckConstraintType, Expression: "price>=(0) AND status<>''"},
+		},
+		Indexes: []schema.Index{
+			{Name: "users_created_at_idx", Columns: []string{"created_at"}, Where: "created_at IS NOT NULL"},
+			{Name: "users_status_price_idx", Columns: []string{"status", "price"}, Unique: true},
+		},
+	}
+	table, err := d.GetTable("users")
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Errorf("GetTable() = %+v, want %+v", table, expected)
+	}
+}
+
+func TestSqlserverColumnType(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		maxLength int64
+		precision int64
+		scale     int64
+		want      schema.DataType
+	}{
+		{typeName: "int", maxLength: 4, precision: 10, want: "INT"},
+		{typeName: "nvarchar", maxLength: 100, want: "NVARCHAR(50)"},
+		{typeName: "nvarchar", maxLength: -1, want: "NVARCHAR(MAX)"},
+		{typeName: "varbinary", maxLength: 16, want: "VARBINARY(16)"},
+		{typeName: "decimal", maxLength: 9, precision: 18, scale: 0, want: "DECIMAL(18,0)"},
+		{typeName: "datetime2", maxLength: 8, precision: 27, scale: 7, want: "DATETIME2"},
+		{typeName: "datetime2", maxLength: 7, precision: 23, scale: 3, want: "DATETIME2(3)"},
+		{typeName: "uniqueidentifier", maxLength: 16, want: "UNIQUEIDENTIFIER"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			got := sqlserverColumnType(tt.typeName, tt.maxLength, tt.precision, tt.scale)
+			if got != tt.want {
+				t.Errorf("sqlserverColumnType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
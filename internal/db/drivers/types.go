@@ -0,0 +1,107 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// typeMapping is the type a driver uses for a logical type. parameterized
+// is used when the logical type has arguments, which replace its %s, and is
+// empty when the type takes none.
+type typeMapping struct {
+	plain         string
+	parameterized string
+}
+
+// typeRules describes the types of a driver: the types logical types map
+// to, the canonical types of aliases, which take the arguments of the
+// canonical type when they have none, and whether types can be unsigned or
+// arrays. normalize, when set, applies the remaining rules of the driver.
+type typeRules struct {
+	logical   map[schema.LogicalType]typeMapping
+	aliases   map[string]schema.DataType
+	unsigned  bool
+	arrays    bool
+	normalize func(schema.ParsedType) schema.ParsedType
+}
+
+// normalizeType parses a type and replaces its aliases. ok is false when the
+// type uses a feature the driver does not support.
+func (r typeRules) normalizeType(t schema.DataType) (schema.ParsedType, bool) {
+	parsed := t.Parse()
+	if alias, ok := r.aliases[parsed.Base]; ok {
+		canonical := alias.Parse()
+		parsed.Base = canonical.Base
+		parsed.ArgumentsPosition = canonical.ArgumentsPosition
+		if len(parsed.Arguments) == 0 {
+			parsed.Arguments = canonical.Arguments
+		}
+	}
+	if r.normalize != nil {
+		parsed = r.normalize(parsed)
+	}
+	return parsed, (r.unsigned || !parsed.Unsigned) && (r.arrays || parsed.ArrayDimensions == 0)
+}
+
+// resolveType returns the normalized driver type for a type, logical types
+// being first mapped to the type of the driver.
+func (r typeRules) resolveType(t schema.DataType) (schema.DataType, errors.Error) {
+	logical, arguments, ok := t.Logical()
+	if ok {
+		mapping, ok := r.logical[logical]
+		if !ok {
+			return "", errors.New(fmt.Sprintf("unsupported logical type: %s", t))
+		}
+		if len(arguments) > logical.MaxArguments() {
+			return "", errors.New(fmt.Sprintf("logical type %s takes at most %d argument(s): %s", logical, logical.MaxArguments(), t))
+		}
+		if len(arguments) == 0 {
+			t = schema.DataType(mapping.plain)
+		} else {
+			t = schema.DataType(fmt.Sprintf(mapping.parameterized, strings.Join(arguments, ",")))
+		}
+	}
+	parsed, _ := r.normalizeType(t)
+	return parsed.DataType(), nil
+}
+
+// ResolveTypes returns a copy of the tables where logical types are
+// replaced by the types of the dialect and aliases by their canonical type.
+func ResolveTypes(d Dialect, tables []schema.Table) ([]schema.Table, errors.Error) {
+	resolved := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		columns := make([]schema.Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			columnType, err := d.ResolveType(column.Type)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("column %s.%s: %s", table.Name, column.Name, err.Display()))
+			}
+			column.Type = columnType
+			columns = append(columns, column)
+		}
+		table.Columns = columns
+		resolved = append(resolved, table)
+	}
+	return resolved, nil
+}
+
+// NormalizeTypes returns a copy of introspected tables where types are
+// written in their canonical form, so that they compare equal to resolved
+// types.
+func NormalizeTypes(d Dialect, tables []schema.Table) []schema.Table {
+	normalized := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		columns := make([]schema.Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			parsed, _ := d.NormalizeType(column.Type)
+			column.Type = parsed.DataType()
+			columns = append(columns, column)
+		}
+		table.Columns = columns
+		normalized = append(normalized, table)
+	}
+	return normalized
+}
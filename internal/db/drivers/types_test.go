@@ -0,0 +1,125 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestResolveType(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		dataType schema.DataType
+		want     schema.DataType
+		wantErr  bool
+	}{
+		{name: "mysql bool", dialect: mysqlDriverInstance, dataType: "bool", want: "TINYINT(1)"},
+		{name: "mysql string", dialect: mysqlDriverInstance, dataType: "string(50)", want: "VARCHAR(50)"},
+		{name: "mysql timestamp", dialect: mysqlDriverInstance, dataType: "timestamp", want: "DATETIME"},
+		{name: "postgres decimal", dialect: postgresDriverInstance, dataType: "decimal(10, 2)", want: "NUMERIC(10,2)"},
+		{name: "postgres json", dialect: postgresDriverInstance, dataType: "json", want: "JSONB"},
+		{name: "postgres string", dialect: postgresDriverInstance, dataType: "string", want: "TEXT"},
+		{name: "sqlite int64", dialect: sqliteDriverInstance, dataType: "int64", want: "INTEGER"},
+		{name: "sqlite uuid", dialect: sqliteDriverInstance, dataType: "uuid", want: "TEXT"},
+		{name: "sqlserver string", dialect: sqlserverDriverInstance, dataType: "string", want: "NVARCHAR(MAX)"},
+		{name: "sqlserver decimal", dialect: sqlserverDriverInstance, dataType: "decimal", want: "DECIMAL(18,0)"},
+		{name: "duckdb string", dialect: duckdbDriverInstance, dataType: "string(50)", want: "VARCHAR"},
+		{name: "duckdb decimal", dialect: duckdbDriverInstance, dataType: "decimal", want: "DECIMAL(18,3)"},
+		{name: "mariadb json", dialect: mariadbDriverInstance, dataType: "json", want: "LONGTEXT"},
+		{name: "mariadb uuid", dialect: mariadbDriverInstance, dataType: "uuid", want: "UUID"},
+		{name: "mariadb 10.6 uuid", dialect: &mariadbDriver{mysqlDriver: &mysqlDriver{version: Version{Major: 10, Minor: 6}}}, dataType: "uuid", want: "CHAR(36)"},
+		{name: "cockroachdb int32", dialect: cockroachdbDriverInstance, dataType: "int32", want: "INT4"},
+		{name: "cockroachdb string", dialect: cockroachdbDriverInstance, dataType: "string(50)", want: "STRING(50)"},
+		{name: "driver type", dialect: sqliteDriverInstance, dataType: "REAL", want: "REAL"},
+		{name: "too many arguments", dialect: postgresDriverInstance, dataType: "bool(1)", wantErr: true},
+		{name: "too many string arguments", dialect: mysqlDriverInstance, dataType: "string(1,2)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dialect.ResolveType(tt.dataType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   Driver
+		dataType schema.DataType
+		want     schema.DataType
+		valid    bool
+	}{
+		{name: "postgres int4", driver: postgresDriverInstance, dataType: "int4", want: "INTEGER", valid: true},
+		{name: "postgres character varying", driver: postgresDriverInstance, dataType: "character varying(20)", want: "VARCHAR(20)", valid: true},
+		{name: "postgres timestamp with time zone", driver: postgresDriverInstance, dataType: "TIMESTAMP(3) WITH TIME ZONE", want: "TIMESTAMPTZ(3)", valid: true},
+		{name: "postgres default time precision", driver: postgresDriverInstance, dataType: "TIMESTAMP(6) WITH TIME ZONE", want: "TIMESTAMPTZ", valid: true},
+		{name: "postgres array", driver: postgresDriverInstance, dataType: "INT4[]", want: "INTEGER[]", valid: true},
+		{name: "postgres unsigned", driver: postgresDriverInstance, dataType: "INTEGER UNSIGNED", want: "INTEGER UNSIGNED"},
+		{name: "mysql display width", driver: mysqlDriverInstance, dataType: "int(11) unsigned", want: "INT UNSIGNED", valid: true},
+		{name: "mysql boolean", driver: mysqlDriverInstance, dataType: "BOOLEAN", want: "TINYINT(1)", valid: true},
+		{name: "mysql tinyint", driver: mysqlDriverInstance, dataType: "TINYINT(4)", want: "TINYINT", valid: true},
+		{name: "mysql decimal", driver: mysqlDriverInstance, dataType: "numeric(10, 2)", want: "DECIMAL(10,2)", valid: true},
+		{name: "mysql array", driver: mysqlDriverInstance, dataType: "INT[]", want: "INT[]"},
+		{name: "sqlserver varchar max", driver: sqlserverDriverInstance, dataType: "varchar(max)", want: "VARCHAR(MAX)", valid: true},
+		{name: "sqlserver datetime2 precision", driver: sqlserverDriverInstance, dataType: "DATETIME2(7)", want: "DATETIME2", valid: true},
+		{name: "sqlserver national character varying", driver: sqlserverDriverInstance, dataType: "national character varying(20)", want: "NVARCHAR(20)", valid: true},
+		{name: "duckdb list", driver: duckdbDriverInstance, dataType: "LIST(int)", want: "INTEGER[]", valid: true},
+		{name: "duckdb struct", driver: duckdbDriverInstance, dataType: "STRUCT(a INT, b TEXT[])", want: "STRUCT(a INTEGER,b VARCHAR[])", valid: true},
+		{name: "duckdb map", driver: duckdbDriverInstance, dataType: "MAP(string, numeric(10))", want: "MAP(VARCHAR,DECIMAL(10,0))", valid: true},
+		{name: "duckdb unsigned", driver: duckdbDriverInstance, dataType: "INTEGER UNSIGNED", want: "INTEGER UNSIGNED"},
+		{name: "mariadb json", driver: mariadbDriverInstance, dataType: "JSON", want: "LONGTEXT", valid: true},
+		{name: "mariadb display width", driver: mariadbDriverInstance, dataType: "bigint(20)", want: "BIGINT", valid: true},
+		{name: "cockroachdb integer", driver: cockroachdbDriverInstance, dataType: "INTEGER", want: "INT8", valid: true},
+		{name: "cockroachdb varchar", driver: cockroachdbDriverInstance, dataType: "character varying(20)", want: "STRING(20)", valid: true},
+		{name: "cockroachdb array", driver: cockroachdbDriverInstance, dataType: "TEXT[]", want: "STRING[]", valid: true},
+		{name: "sqlite keeps int", driver: sqliteDriverInstance, dataType: "int", want: "INT", valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, ok := tt.driver.NormalizeType(tt.dataType)
+			if parsed.DataType() != tt.want || ok != tt.valid {
+				t.Errorf("NormalizeType() = %q, %v, want %q, %v", parsed.DataType(), ok, tt.want, tt.valid)
+			}
+		})
+	}
+}
+
+func TestHasType(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   Driver
+		dataType schema.DataType
+		want     bool
+	}{
+		{name: "postgres varchar", driver: postgresDriverInstance, dataType: "VARCHAR(255)", want: true},
+		{name: "postgres alias", driver: postgresDriverInstance, dataType: "character varying", want: true},
+		{name: "postgres timestamp precision", driver: postgresDriverInstance, dataType: "TIMESTAMP(6)", want: true},
+		{name: "postgres array", driver: postgresDriverInstance, dataType: "TEXT[]", want: true},
+		{name: "mysql unsigned decimal", driver: mysqlDriverInstance, dataType: "DECIMAL(10,2) UNSIGNED", want: true},
+		{name: "sqlite unsigned", driver: sqliteDriverInstance, dataType: "INTEGER UNSIGNED", want: false},
+		{name: "unknown type", driver: postgresDriverInstance, dataType: "VARCHAR2(10)", want: false},
+		{name: "duckdb struct", driver: duckdbDriverInstance, dataType: "STRUCT(a INTEGER)", want: true},
+		{name: "mariadb inet6", driver: mariadbDriverInstance, dataType: "INET6", want: true},
+		{name: "mysql inet6", driver: mysqlDriverInstance, dataType: "INET6", want: false},
+		{name: "cockroachdb bytea", driver: cockroachdbDriverInstance, dataType: "BYTEA", want: true},
+		{name: "cockroachdb xml", driver: cockroachdbDriverInstance, dataType: "XML", want: false},
+		{name: "mysql 5.7.7 json", driver: &mysqlDriver{version: Version{Major: 5, Minor: 7, Patch: 7}, dataTypes: mysqlDataTypes, typeVersions: mysqlDriverInstance.typeVersions}, dataType: "JSON", want: false},
+		{name: "mariadb 10.6 uuid", driver: &mariadbDriver{mysqlDriver: &mysqlDriver{version: Version{Major: 10, Minor: 6}, dataTypes: mariadbDriverInstance.dataTypes, typeVersions: mariadbDriverInstance.typeVersions}}, dataType: "UUID", want: false},
+		{name: "mariadb 10.6 logical uuid", driver: &mariadbDriver{mysqlDriver: &mysqlDriver{version: Version{Major: 10, Minor: 6}, dataTypes: mariadbDriverInstance.dataTypes, typeVersions: mariadbDriverInstance.typeVersions}}, dataType: "uuid", want: true},
+		{name: "logical type", driver: sqliteDriverInstance, dataType: "string(10)", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasType(tt.driver, tt.dataType); got != tt.want {
+				t.Errorf("HasType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
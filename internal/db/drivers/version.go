@@ -0,0 +1,91 @@
+package drivers
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// Version is the version of a database server, compared as a semantic
+// version. Drivers assume a version until they are connected, the oldest
+// one supporting all the statements they generate by default.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var versionNumber = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// ParseVersion reads the first version number of a version string as
+// reported by a server, e.g. 8.0.36-log, 10.11.6-MariaDB-log or
+// CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu).
+func ParseVersion(version string) (Version, errors.Error) {
+	match := versionNumber.FindStringSubmatch(version)
+	if match == nil {
+		return Version{}, errors.New(fmt.Sprintf("invalid server version: %s", version))
+	}
+	numbers := make([]int, 0, 3)
+	for _, number := range match[1:] {
+		n, _ := strconv.Atoi(number)
+		numbers = append(numbers, n)
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// serverVersion queries the version of a server with a query returning it
+// as a single value.
+func serverVersion(db *sql.DB, query string) (Version, errors.Error) {
+	var version string
+	if err := db.QueryRow(query).Scan(&version); err != nil {
+		return Version{}, errors.New(fmt.Sprintf("Could not query server version!\n%s", err))
+	}
+	return ParseVersion(version)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when the version is older than, the same as
+// or newer than other.
+func (v Version) Compare(other Version) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		switch {
+		case diff < 0:
+			return -1
+		case diff > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether the version is other or a newer one.
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// availableTypes returns the types of a driver available in a version of
+// the server. since holds the version introducing a type, the others being
+// available in every version.
+func availableTypes(dataTypes []schema.DataType, since map[schema.DataType]Version, version Version) []schema.DataType {
+	available := make([]schema.DataType, 0, len(dataTypes))
+	for _, dataType := range dataTypes {
+		if introduced, ok := since[dataType]; ok && !version.AtLeast(introduced) {
+			continue
+		}
+		available = append(available, dataType)
+	}
+	return available
+}
+
+// unsupportedFeature is the error returned for a statement the server
+// version does not support.
+func unsupportedFeature(driver string, version Version, feature string, since Version) errors.Error {
+	return errors.New(fmt.Sprintf("%s %s does not support %s, %s or later is required", driver, version, feature, since))
+}
@@ -0,0 +1,59 @@
+package drivers
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    Version
+		wantErr bool
+	}{
+		{version: "8.0.36", want: Version{Major: 8, Minor: 0, Patch: 36}},
+		{version: "5.7.44-log", want: Version{Major: 5, Minor: 7, Patch: 44}},
+		{version: "10.11.6-MariaDB-1:10.11.6+maria~ubu2204", want: Version{Major: 10, Minor: 11, Patch: 6}},
+		{version: "CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, built 2023/09/27)", want: Version{Major: 23, Minor: 1, Patch: 11}},
+		{version: "v1.1.3", want: Version{Major: 1, Minor: 1, Patch: 3}},
+		{version: "16.0.1000.6", want: Version{Major: 16, Minor: 0, Patch: 1000}},
+		{version: "3", want: Version{Major: 3}},
+		{version: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresVersion(t *testing.T) {
+	tests := map[int]Version{
+		150004: {Major: 15, Minor: 4},
+		100000: {Major: 10},
+		90624:  {Major: 9, Minor: 6, Patch: 24},
+	}
+	for number, want := range tests {
+		if got := postgresVersion(number); got != want {
+			t.Errorf("postgresVersion(%d) = %s, want %s", number, got, want)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	version := Version{Major: 8, Minor: 0, Patch: 16}
+	if !version.AtLeast(Version{Major: 8, Minor: 0, Patch: 16}) || !version.AtLeast(Version{Major: 5, Minor: 7, Patch: 44}) {
+		t.Errorf("%s should be at least 8.0.16 and 5.7.44", version)
+	}
+	if version.AtLeast(Version{Major: 8, Minor: 1, Patch: 0}) || version.AtLeast(Version{Major: 10, Minor: 0, Patch: 0}) {
+		t.Errorf("%s should be older than 8.1.0 and 10.0.0", version)
+	}
+	if version.Compare(Version{Major: 8, Minor: 0, Patch: 16}) != 0 {
+		t.Errorf("%s should equal itself", version)
+	}
+}
@@ -0,0 +1,62 @@
+package db
+
+// ValidationCode identifies the kind of problem found in a schema, so that
+// tools can react to it without parsing the message.
+type ValidationCode string
+
+const (
+	MissingDriverCode       ValidationCode = "missing_driver"
+	InvalidDriverCode       ValidationCode = "invalid_driver"
+	MissingNameCode         ValidationCode = "missing_name"
+	MissingTablesCode       ValidationCode = "missing_tables"
+	DuplicateNameCode       ValidationCode = "duplicate_name"
+	InvalidNameCode         ValidationCode = "invalid_name"
+	InvalidRenameCode       ValidationCode = "invalid_rename"
+	MissingTypeCode         ValidationCode = "missing_type"
+	InvalidTypeCode         ValidationCode = "invalid_type"
+	InvalidConstraintCode   ValidationCode = "invalid_constraint"
+	MultiplePrimaryKeysCode ValidationCode = "multiple_primary_keys"
+	InvalidIndexCode        ValidationCode = "invalid_index"
+	UnknownTableCode        ValidationCode = "unknown_table"
+	UnknownColumnCode       ValidationCode = "unknown_column"
+	InvalidSchemaFileCode   ValidationCode = "invalid_schema_file"
+	AmbiguousTypeCode       ValidationCode = "ambiguous_type"
+)
+
+// ValidationError is a problem found in a schema. Table and Column locate
+// it and are empty when it concerns the whole database or table. A warning
+// does not make the schema invalid.
+type ValidationError struct {
+	Code    ValidationCode `json:"code" yaml:"code"`
+	Table   string         `json:"table,omitempty" yaml:"table,omitempty"`
+	Column  string         `json:"column,omitempty" yaml:"column,omitempty"`
+	Message string         `json:"message" yaml:"message"`
+	Warning bool           `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
+
+func validationError(code ValidationCode, table string, column string, message string) ValidationError {
+	return ValidationError{Code: code, Table: table, Column: column, Message: message}
+}
+
+func validationWarning(code ValidationCode, table string, column string, message string) ValidationError {
+	return ValidationError{Code: code, Table: table, Column: column, Message: message, Warning: true}
+}
+
+// WithoutWarnings returns the validation errors which make a schema invalid.
+func WithoutWarnings(errs []ValidationError) []ValidationError {
+	filtered := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		if !err.Warning {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+func (e ValidationError) Display() string {
+	return e.Message
+}
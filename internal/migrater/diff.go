@@ -0,0 +1,330 @@
+package migrater
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+type ChangeKind string
+
+const (
+	RenameTableChange         ChangeKind = "rename_table"
+	RenameColumnChange        ChangeKind = "rename_column"
+	DropIndexChange           ChangeKind = "drop_index"
+	DropTableConstraintChange ChangeKind = "drop_table_constraint"
+	DropConstraintChange      ChangeKind = "drop_constraint"
+	AddTableChange            ChangeKind = "add_table"
+	AddColumnChange           ChangeKind = "add_column"
+	AlterColumnTypeChange     ChangeKind = "alter_column_type"
+	AddConstraintChange       ChangeKind = "add_constraint"
+	AddTableConstraintChange  ChangeKind = "add_table_constraint"
+	AddIndexChange            ChangeKind = "add_index"
+	DropColumnChange          ChangeKind = "drop_column"
+	DropTableChange           ChangeKind = "drop_table"
+)
+
+// changeOrder is the order in which changes are applied: tables and columns
+// are renamed first so that the other changes can use their new names,
+// indexes and constraints are dropped before the columns and tables they
+// reference are altered, indexes are added once their columns exist, and
+// tables are dropped last.
+var changeOrder = []ChangeKind{
+	RenameTableChange,
+	RenameColumnChange,
+	DropIndexChange,
+	DropTableConstraintChange,
+	DropConstraintChange,
+	AddTableChange,
+	AddColumnChange,
+	AlterColumnTypeChange,
+	AddConstraintChange,
+	AddTableConstraintChange,
+	AddIndexChange,
+	DropColumnChange,
+	DropTableChange,
+}
+
+// Change is a single difference between the desired and the live schema.
+// Table is always set, Column is set for column and constraint changes and
+// holds the desired column (or the live one when it is dropped). Previous
+// holds the live column when its type changes or it is renamed, and
+// PreviousTable the live table when it is renamed. TableConstraint and
+// Index are set for table constraint and index changes. Cycle holds the
+// tables of the foreign key cycle a foreign key was split from, to be added
+// once the tables are created or dropped before they are dropped.
+type Change struct {
+	Kind            ChangeKind
+	Table           schema.Table
+	PreviousTable   schema.Table
+	Column          schema.Column
+	Previous        schema.Column
+	Constraint      schema.Constraint
+	TableConstraint schema.TableConstraint
+	Index           schema.Index
+	Cycle           []string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case AddTableChange:
+		return fmt.Sprintf("+ table %s", c.Table.Name)
+	case DropTableChange:
+		return fmt.Sprintf("- table %s", c.Table.Name)
+	case RenameTableChange:
+		return fmt.Sprintf("~ table %s -> %s", c.PreviousTable.Name, c.Table.Name)
+	case RenameColumnChange:
+		return fmt.Sprintf("~ column %s.%s -> %s", c.Table.Name, c.Previous.Name, c.Column.Name)
+	case AddColumnChange:
+		return fmt.Sprintf("+ column %s.%s %s", c.Table.Name, c.Column.Name, c.Column.Type)
+	case DropColumnChange:
+		return fmt.Sprintf("- column %s.%s", c.Table.Name, c.Column.Name)
+	case AlterColumnTypeChange:
+		return fmt.Sprintf("~ column %s.%s type %s -> %s", c.Table.Name, c.Column.Name, c.Previous.Type, c.Column.Type)
+	case AddConstraintChange:
+		return fmt.Sprintf("+ constraint %s on %s.%s", c.Constraint.Name(), c.Table.Name, c.Column.Name)
+	case DropConstraintChange:
+		return fmt.Sprintf("- constraint %s on %s.%s", c.Constraint.Name(), c.Table.Name, c.Column.Name)
+	case AddTableConstraintChange:
+		return fmt.Sprintf("+ constraint %s on %s", c.TableConstraint, c.Table.Name)
+	case DropTableConstraintChange:
+		return fmt.Sprintf("- constraint %s on %s", c.TableConstraint.Name, c.Table.Name)
+	case AddIndexChange:
+		return fmt.Sprintf("+ index %s on %s", c.Index, c.Table.Name)
+	case DropIndexChange:
+		return fmt.Sprintf("- index %s on %s", c.Index.Name, c.Table.Name)
+	default:
+		return string(c.Kind)
+	}
+}
+
+// IsDestructive reports whether applying the change can lose data or fail
+// on existing rows: dropping a table or a column, narrowing the type of a
+// column, or making an existing column NOT NULL without a default.
+func (c Change) IsDestructive() bool {
+	switch c.Kind {
+	case DropTableChange, DropColumnChange:
+		return true
+	case AlterColumnTypeChange:
+		return !widensType(c.Previous.Type, c.Column.Type)
+	case AddConstraintChange:
+		_, notNull := c.Constraint.(schema.NotNullConstraint)
+		return notNull && !hasDefault(c.Column)
+	default:
+		return false
+	}
+}
+
+type ChangeSet []Change
+
+func (s ChangeSet) IsEmpty() bool {
+	return len(s) == 0
+}
+
+func (s ChangeSet) String() string {
+	if s.IsEmpty() {
+		return "No changes."
+	}
+	lines := make([]string, 0, len(s))
+	for _, change := range s {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Compare computes the changes needed to turn the live tables into the
+// desired ones. Tables and columns whose previous name is given are renamed
+// when the previous name exists in the live schema and no longer in the
+// desired one.
+func Compare(desired []schema.Table, live []schema.Table) ChangeSet {
+	changes, renames := findRenames(desired, live)
+	live = renames.apply(live)
+	liveTables := make(map[string]schema.Table, len(live))
+	for _, table := range live {
+		liveTables[table.Name] = table
+	}
+	desiredTables := make(map[string]bool, len(desired))
+	for _, table := range desired {
+		desiredTables[table.Name] = true
+		liveTable, ok := liveTables[table.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: AddTableChange, Table: table})
+			for _, index := range table.Indexes {
+				changes = append(changes, Change{Kind: AddIndexChange, Table: table, Index: index})
+			}
+			continue
+		}
+		changes = append(changes, compareTables(table, liveTable)...)
+	}
+	for _, table := range live {
+		if !desiredTables[table.Name] {
+			changes = append(changes, Change{Kind: DropTableChange, Table: table})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changeRank(changes[i].Kind) < changeRank(changes[j].Kind)
+	})
+	return changes
+}
+
+func compareTables(desired schema.Table, live schema.Table) ChangeSet {
+	changes := make(ChangeSet, 0)
+	liveColumns := make(map[string]schema.Column, len(live.Columns))
+	for _, column := range live.Columns {
+		liveColumns[column.Name] = column
+	}
+	desiredColumns := make(map[string]bool, len(desired.Columns))
+	for _, column := range desired.Columns {
+		desiredColumns[column.Name] = true
+		liveColumn, ok := liveColumns[column.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: AddColumnChange, Table: desired, Column: column})
+			continue
+		}
+		changes = append(changes, compareColumns(desired, column, liveColumn)...)
+	}
+	for _, column := range live.Columns {
+		if !desiredColumns[column.Name] {
+			changes = append(changes, Change{Kind: DropColumnChange, Table: desired, Column: column})
+		}
+	}
+	changes = append(changes, compareTableConstraints(desired, live)...)
+	return append(changes, compareIndexes(desired, live)...)
+}
+
+// compareTableConstraints matches table constraints by name, a constraint
+// whose definition changed is dropped and added again.
+func compareTableConstraints(desired schema.Table, live schema.Table) ChangeSet {
+	changes := make(ChangeSet, 0)
+	liveConstraints := make(map[string]schema.TableConstraint, len(live.Constraints))
+	for _, constraint := range live.Constraints {
+		liveConstraints[constraint.Name] = constraint
+	}
+	desiredConstraints := make(map[string]bool, len(desired.Constraints))
+	for _, constraint := range desired.Constraints {
+		desiredConstraints[constraint.Name] = true
+		liveConstraint, ok := liveConstraints[constraint.Name]
+		if ok && liveConstraint.Equals(constraint) {
+			continue
+		}
+		if ok {
+			changes = append(changes, Change{Kind: DropTableConstraintChange, Table: desired, TableConstraint: liveConstraint})
+		}
+		changes = append(changes, Change{Kind: AddTableConstraintChange, Table: desired, TableConstraint: constraint})
+	}
+	for _, constraint := range live.Constraints {
+		if !desiredConstraints[constraint.Name] {
+			changes = append(changes, Change{Kind: DropTableConstraintChange, Table: desired, TableConstraint: constraint})
+		}
+	}
+	return changes
+}
+
+// compareIndexes matches indexes by name, an index whose definition changed
+// is dropped and created again.
+func compareIndexes(desired schema.Table, live schema.Table) ChangeSet {
+	changes := make(ChangeSet, 0)
+	liveIndexes := make(map[string]schema.Index, len(live.Indexes))
+	for _, index := range live.Indexes {
+		liveIndexes[index.Name] = index
+	}
+	desiredIndexes := make(map[string]bool, len(desired.Indexes))
+	for _, index := range desired.Indexes {
+		desiredIndexes[index.Name] = true
+		liveIndex, ok := liveIndexes[index.Name]
+		if ok && liveIndex.Equals(index) {
+			continue
+		}
+		if ok {
+			changes = append(changes, Change{Kind: DropIndexChange, Table: desired, Index: liveIndex})
+		}
+		changes = append(changes, Change{Kind: AddIndexChange, Table: desired, Index: index})
+	}
+	for _, index := range live.Indexes {
+		if !desiredIndexes[index.Name] {
+			changes = append(changes, Change{Kind: DropIndexChange, Table: desired, Index: index})
+		}
+	}
+	return changes
+}
+
+func compareColumns(table schema.Table, desired schema.Column, live schema.Column) ChangeSet {
+	changes := make(ChangeSet, 0)
+	if !desired.Type.Equals(live.Type) {
+		changes = append(changes, Change{Kind: AlterColumnTypeChange, Table: table, Column: desired, Previous: live})
+	}
+	desiredConstraints := desired.GetConstraints()
+	liveConstraints := live.GetConstraints()
+	if impliesNotNull(desiredConstraints) && impliesNotNull(liveConstraints) {
+		desiredConstraints = withNotNull(desiredConstraints)
+		liveConstraints = withNotNull(liveConstraints)
+	}
+	for _, constraint := range desiredConstraints {
+		if !schema.HasConstraint(liveConstraints, constraint) {
+			changes = append(changes, Change{Kind: AddConstraintChange, Table: table, Column: desired, Constraint: constraint})
+		}
+	}
+	for _, constraint := range liveConstraints {
+		if !schema.HasConstraint(desiredConstraints, constraint) {
+			changes = append(changes, Change{Kind: DropConstraintChange, Table: table, Column: desired, Constraint: constraint})
+		}
+	}
+	return changes
+}
+
+// impliesNotNull reports whether the constraints make a column NOT NULL
+// without declaring it.
+func impliesNotNull(constraints []schema.Constraint) bool {
+	return schema.HasConstraint(constraints, schema.PrimaryKeyConstraint{}) || schema.HasConstraint(constraints, schema.AutoIncrementConstraint{})
+}
+
+// withNotNull adds the NOT NULL implied by a key or identity, which databases
+// report on the column while schemas usually leave it out.
+func withNotNull(constraints []schema.Constraint) []schema.Constraint {
+	if schema.HasConstraint(constraints, schema.NotNullConstraint{}) {
+		return constraints
+	}
+	return append(constraints, schema.NotNullConstraint{})
+}
+
+func hasDefault(column schema.Column) bool {
+	for _, constraint := range column.GetConstraints() {
+		if _, ok := constraint.(schema.DefaultConstraint); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DestructiveChanges returns the destructive changes of the set.
+func (s ChangeSet) DestructiveChanges() ChangeSet {
+	destructive := make(ChangeSet, 0)
+	for _, change := range s {
+		if change.IsDestructive() {
+			destructive = append(destructive, change)
+		}
+	}
+	return destructive
+}
+
+// Cycles returns the foreign keys split from the cycles of the set.
+func (s ChangeSet) Cycles() ChangeSet {
+	cycles := make(ChangeSet, 0)
+	for _, change := range s {
+		if len(change.Cycle) > 0 {
+			cycles = append(cycles, change)
+		}
+	}
+	return cycles
+}
+
+func changeRank(kind ChangeKind) int {
+	for i, k := range changeOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(changeOrder)
+}
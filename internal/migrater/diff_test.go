@@ -0,0 +1,409 @@
+package migrater
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestCompare(t *testing.T) {
+	users := schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}},
+			{Name: "name", Type: "TEXT"},
+		},
+	}
+	posts := schema.Table{
+		Name: "posts",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+		},
+	}
+	tests := []struct {
+		name    string
+		desired []schema.Table
+		live    []schema.Table
+		want    []string
+	}{
+		{
+			name:    "no changes",
+			desired: []schema.Table{users},
+			live:    []schema.Table{users},
+			want:    []string{},
+		},
+		{
+			name:    "type comparison is case insensitive",
+			desired: []schema.Table{users},
+			live: []schema.Table{{
+				Name: "users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "integer", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}},
+					{Name: "name", Type: "text"},
+				},
+			}},
+			want: []string{},
+		},
+		{
+			name:    "primary key implies not null",
+			desired: []schema.Table{users},
+			live: []schema.Table{{
+				Name: "users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.NotNullConstraint{}, schema.PrimaryKeyConstraint{}}},
+					{Name: "name", Type: "TEXT"},
+				},
+			}},
+			want: []string{},
+		},
+		{
+			name:    "added and dropped tables",
+			desired: []schema.Table{users},
+			live:    []schema.Table{posts},
+			want:    []string{"+ table users", "- table posts"},
+		},
+		{
+			name: "changed foreign key actions",
+			desired: []schema.Table{{
+				Name: "posts",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"}}},
+				},
+			}},
+			live: []schema.Table{{
+				Name: "posts",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id"}}},
+				},
+			}},
+			want: []string{
+				"- constraint ForeignKey(users.id) on posts.user_id",
+				"+ constraint ForeignKey(users.id ON DELETE CASCADE) on posts.user_id",
+			},
+		},
+		{
+			name: "foreign key actions are case insensitive",
+			desired: []schema.Table{{
+				Name: "posts",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "cascade"}}},
+				},
+			}},
+			live: []schema.Table{{
+				Name: "posts",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"}}},
+				},
+			}},
+			want: []string{},
+		},
+		{
+			name: "column changes",
+			desired: []schema.Table{{
+				Name: "users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "BIGINT", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}},
+					{Name: "email", Type: "TEXT", Constraints: []schema.Constraint{schema.NotNullConstraint{}}},
+				},
+			}},
+			live: []schema.Table{users},
+			want: []string{
+				"+ column users.email TEXT",
+				"~ column users.id type INTEGER -> BIGINT",
+				"- column users.name",
+			},
+		},
+		{
+			name: "constraint changes",
+			desired: []schema.Table{{
+				Name: "users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "INTEGER"},
+					{Name: "name", Type: "TEXT", Default: "'anonymous'", Constraints: []schema.Constraint{schema.NotNullConstraint{}}},
+				},
+			}},
+			live: []schema.Table{users},
+			want: []string{
+				"- constraint PrimaryKey on users.id",
+				"+ constraint NotNull on users.name",
+				"+ constraint Default('anonymous') on users.name",
+			},
+		},
+		{
+			name: "index changes",
+			desired: []schema.Table{
+				withIndexes(users, schema.Index{Name: "users_name_idx", Columns: []string{"name"}, Unique: true}),
+				withIndexes(posts, schema.Index{Name: "posts_id_idx", Columns: []string{"id"}}),
+			},
+			live: []schema.Table{withIndexes(users,
+				schema.Index{Name: "users_name_idx", Columns: []string{"name"}},
+				schema.Index{Name: "users_id_idx", Columns: []string{"id"}, Method: "hash"},
+			)},
+			want: []string{
+				"- index users_name_idx on users",
+				"- index users_id_idx on users",
+				"+ table posts",
+				"+ index users_name_idx (name) UNIQUE on users",
+				"+ index posts_id_idx (id) on posts",
+			},
+		},
+		{
+			name: "table constraint changes",
+			desired: []schema.Table{withConstraints(users,
+				schema.TableConstraint{Name: "users_id_name_key", Type: schema.UniqueConstraintType, Columns: []string{"id", "name"}},
+				schema.TableConstraint{Name: "users_name_check", Type: schema.CheckConstraintType, Expression: "name <> ''"},
+			)},
+			live: []schema.Table{withConstraints(users,
+				schema.TableConstraint{Name: "users_id_name_key", Type: schema.UniqueConstraintType, Columns: []string{"name", "id"}},
+				schema.TableConstraint{Name: "users_old_check", Type: schema.CheckConstraintType, Expression: "id > 0"},
+			)},
+			want: []string{
+				"- constraint users_id_name_key on users",
+				"- constraint users_old_check on users",
+				"+ constraint users_id_name_key UNIQUE (id, name) on users",
+				"+ constraint users_name_check CHECK (name <> '') on users",
+			},
+		},
+		{
+			name: "renamed table and column",
+			desired: []schema.Table{
+				{
+					Name:        "accounts",
+					RenamedFrom: "users",
+					Columns: []schema.Column{
+						{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}},
+						{Name: "full_name", RenamedFrom: "name", Type: "TEXT"},
+					},
+					Indexes: []schema.Index{{Name: "users_name_idx", Columns: []string{"full_name"}}},
+				},
+				{
+					Name: "posts",
+					Columns: []schema.Column{
+						{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "accounts", ReferencedColumn: "id"}}},
+					},
+				},
+			},
+			live: []schema.Table{
+				withIndexes(users, schema.Index{Name: "users_name_idx", Columns: []string{"name"}}),
+				{
+					Name: "posts",
+					Columns: []schema.Column{
+						{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id"}}},
+					},
+				},
+			},
+			want: []string{
+				"~ table users -> accounts",
+				"~ column accounts.name -> full_name",
+			},
+		},
+		{
+			name: "rename hint without previous column",
+			desired: []schema.Table{{
+				Name: "users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "INTEGER", Constraints: []schema.Constraint{schema.PrimaryKeyConstraint{}}},
+					{Name: "name", Type: "TEXT"},
+					{Name: "nickname", RenamedFrom: "alias", Type: "TEXT"},
+				},
+			}},
+			live: []schema.Table{users},
+			want: []string{"+ column users.nickname TEXT"},
+		},
+		{
+			name: "rename hint with previous name still desired",
+			desired: []schema.Table{users, {
+				Name:        "people",
+				RenamedFrom: "users",
+				Columns:     []schema.Column{{Name: "id", Type: "INTEGER"}},
+			}},
+			live: []schema.Table{users},
+			want: []string{"+ table people"},
+		},
+		{
+			name:    "btree is the default index method",
+			desired: []schema.Table{withIndexes(users, schema.Index{Name: "users_name_idx", Columns: []string{"name"}, Method: "BTREE"})},
+			live:    []schema.Table{withIndexes(users, schema.Index{Name: "users_name_idx", Columns: []string{"name"}})},
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(tt.desired, tt.live)
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func withIndexes(table schema.Table, indexes ...schema.Index) schema.Table {
+	table.Indexes = indexes
+	return table
+}
+
+func withConstraints(table schema.Table, constraints ...schema.TableConstraint) schema.Table {
+	table.Constraints = constraints
+	return table
+}
+
+func TestIsDestructive(t *testing.T) {
+	users := schema.Table{Name: "users"}
+	tests := []struct {
+		name   string
+		change Change
+		want   bool
+	}{
+		{
+			name:   "drop table",
+			change: Change{Kind: DropTableChange, Table: users},
+			want:   true,
+		},
+		{
+			name:   "drop column",
+			change: Change{Kind: DropColumnChange, Table: users, Column: schema.Column{Name: "email", Type: "TEXT"}},
+			want:   true,
+		},
+		{
+			name:   "add column",
+			change: Change{Kind: AddColumnChange, Table: users, Column: schema.Column{Name: "email", Type: "TEXT"}},
+			want:   false,
+		},
+		{
+			name: "narrow type",
+			change: Change{
+				Kind:     AlterColumnTypeChange,
+				Table:    users,
+				Column:   schema.Column{Name: "name", Type: "VARCHAR(50)"},
+				Previous: schema.Column{Name: "name", Type: "VARCHAR(255)"},
+			},
+			want: true,
+		},
+		{
+			name: "widen type",
+			change: Change{
+				Kind:     AlterColumnTypeChange,
+				Table:    users,
+				Column:   schema.Column{Name: "name", Type: "VARCHAR(255)"},
+				Previous: schema.Column{Name: "name", Type: "VARCHAR(50)"},
+			},
+			want: false,
+		},
+		{
+			name: "not null without default",
+			change: Change{
+				Kind:       AddConstraintChange,
+				Table:      users,
+				Column:     schema.Column{Name: "name", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+				Constraint: schema.NotNullConstraint{},
+			},
+			want: true,
+		},
+		{
+			name: "not null with default",
+			change: Change{
+				Kind:       AddConstraintChange,
+				Table:      users,
+				Column:     schema.Column{Name: "name", Type: "TEXT", Default: "''", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+				Constraint: schema.NotNullConstraint{},
+			},
+			want: false,
+		},
+		{
+			name: "unique",
+			change: Change{
+				Kind:       AddConstraintChange,
+				Table:      users,
+				Column:     schema.Column{Name: "name", Type: "TEXT"},
+				Constraint: schema.UniqueConstraint{},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.IsDestructive(); got != tt.want {
+				t.Errorf("IsDestructive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWidensType(t *testing.T) {
+	tests := []struct {
+		previous schema.DataType
+		next     schema.DataType
+		want     bool
+	}{
+		{"VARCHAR(50)", "VARCHAR(255)", true},
+		{"VARCHAR(255)", "varchar(50)", false},
+		{"VARCHAR(255)", "TEXT", true},
+		{"TEXT", "VARCHAR(255)", false},
+		{"SMALLINT", "INTEGER", true},
+		{"BIGINT", "INT", false},
+		{"DECIMAL(10, 2)", "DECIMAL(12,2)", true},
+		{"DECIMAL(10,2)", "DECIMAL(10,4)", false},
+		{"INTEGER", "TEXT", false},
+		{"CHAR(50)", "CHAR", false},
+		{"VARCHAR(255)", "VARCHAR", false},
+		{"DECIMAL(10,2)", "DECIMAL", false},
+		{"TIMESTAMP(6)", "TIMESTAMP", false},
+		{"VARCHAR", "VARCHAR(255)", false},
+		{"TIMESTAMP", "timestamp", true},
+	}
+	for _, tt := range tests {
+		if got := widensType(tt.previous, tt.next); got != tt.want {
+			t.Errorf("widensType(%s, %s) = %v, want %v", tt.previous, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestWidensTypeDialectDefaults(t *testing.T) {
+	// SQL Server DECIMAL stands for DECIMAL(18,0).
+	sqlserver := drivers.GetDriver(drivers.SqlserverDriverType)
+	next, _ := sqlserver.NormalizeType("DECIMAL")
+	if !widensType("DECIMAL(10,0)", next.DataType()) {
+		t.Errorf("widensType(DECIMAL(10,0), %s) = false, want true", next.DataType())
+	}
+	if widensType("DECIMAL(10,2)", next.DataType()) {
+		t.Errorf("widensType(DECIMAL(10,2), %s) = true, want false", next.DataType())
+	}
+}
+
+func TestWithoutAppliedRenames(t *testing.T) {
+	tables := []schema.Table{{
+		Name:        "accounts",
+		RenamedFrom: "users",
+		Columns: []schema.Column{
+			{Name: "full_name", RenamedFrom: "name", Type: "TEXT"},
+			{Name: "email", RenamedFrom: "mail", Type: "TEXT"},
+		},
+	}}
+	entries := []HistoryEntry{
+		{Status: HistorySuccess, Renames: []HistoryRename{
+			{Kind: RenameTableChange, Table: "accounts", Previous: "users"},
+			{Kind: RenameColumnChange, Table: "accounts", Column: "full_name", Previous: "name"},
+		}},
+		{Status: HistoryFailed, Renames: []HistoryRename{{Kind: RenameColumnChange, Table: "accounts", Column: "email", Previous: "mail"}}},
+	}
+	want := []schema.Table{{
+		Name: "accounts",
+		Columns: []schema.Column{
+			{Name: "full_name", Type: "TEXT"},
+			{Name: "email", RenamedFrom: "mail", Type: "TEXT"},
+		},
+	}}
+	got := withoutAppliedRenames(tables, entries)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("withoutAppliedRenames() = %v, want %v", got, want)
+	}
+	if tables[0].RenamedFrom != "users" {
+		t.Errorf("withoutAppliedRenames() changed its input: %v", tables)
+	}
+}
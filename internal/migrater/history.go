@@ -0,0 +1,288 @@
+package migrater
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/db"
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// HistoryTableName is the table where applied migrations are recorded. It
+// is created in the target database on the first migration and is never
+// part of the compared schema.
+const HistoryTableName = "migrater_history"
+
+// HistoryPending is the status of a migration recorded before its
+// statements run on a dialect which commits them immediately, and left when
+// it could not be completed.
+const (
+	HistorySuccess = "success"
+	HistoryFailed  = "failed"
+	HistoryPending = "pending"
+)
+
+// historyTimeFormat is a fixed width UTC format so that entries sort by
+// their applied_at column.
+const historyTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+var historyTable = schema.Table{
+	Name: HistoryTableName,
+	Columns: []schema.Column{
+		{Name: "applied_at", Type: "VARCHAR(32)", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "schema_hash", Type: "VARCHAR(64)", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "tool_version", Type: "VARCHAR(32)", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "status", Type: "VARCHAR(16)", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "changes", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "statements", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "message", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		{Name: "applied_schema", Type: "TEXT", Constraints: schema.Constraints{schema.NotNullConstraint{}}},
+		// renames was added to existing history tables, which have no value
+		// for their entries.
+		{Name: "renames", Type: "TEXT"},
+	},
+}
+
+// historyTableDefinition returns the history table with its TEXT columns in
+// the large text type of the dialect, since the statements and schemas they
+// hold can outgrow TEXT.
+func historyTableDefinition(dialect drivers.Dialect) schema.Table {
+	table := historyTable
+	table.Columns = make([]schema.Column, 0, len(historyTable.Columns))
+	for _, column := range historyTable.Columns {
+		if column.Type == "TEXT" {
+			column.Type = dialect.LargeTextType()
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	return table
+}
+
+// HistoryEntry is a change set applied, or attempted, on a database.
+type HistoryEntry struct {
+	AppliedAt   time.Time
+	SchemaHash  string
+	ToolVersion string
+	Status      string
+	Changes     []string
+	Statements  []string
+	Message     string
+	Schema      []schema.Table
+	Renames     []HistoryRename
+}
+
+// HistoryRename is a rename of a table or a column applied by a migration.
+// Table is the new name of the table, and Column the new name of a renamed
+// column.
+type HistoryRename struct {
+	Kind     ChangeKind `json:"kind"`
+	Table    string     `json:"table"`
+	Column   string     `json:"column,omitempty"`
+	Previous string     `json:"previous"`
+}
+
+// hashSchema returns the hash identifying a desired schema.
+func hashSchema(tables []schema.Table) (string, errors.Error) {
+	data, err := json.Marshal(tables)
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func hasHistoryTable(database db.Database) bool {
+	_, ok := liveHistoryTable(database)
+	return ok
+}
+
+func liveHistoryTable(database db.Database) (schema.Table, bool) {
+	for _, table := range database.GetTables() {
+		if table.Name == HistoryTableName {
+			return table, true
+		}
+	}
+	return schema.Table{}, false
+}
+
+// withoutHistoryTable filters the history table out of a schema.
+func withoutHistoryTable(tables []schema.Table) []schema.Table {
+	filtered := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		if table.Name != HistoryTableName {
+			filtered = append(filtered, table)
+		}
+	}
+	return filtered
+}
+
+// ensureHistoryTable creates the history table, or adds the columns it
+// lacks when it was created by an older version.
+func ensureHistoryTable(database db.Database, dialect drivers.Dialect) errors.Error {
+	table := historyTableDefinition(dialect)
+	var statements []string
+	if live, ok := liveHistoryTable(database); ok {
+		for _, column := range table.Columns {
+			if slices.ContainsFunc(live.Columns, func(c schema.Column) bool { return c.Name == column.Name }) {
+				continue
+			}
+			added, err := dialect.AddColumn(table, column)
+			if err != nil {
+				return err
+			}
+			statements = append(statements, added...)
+		}
+	} else {
+		created, err := dialect.CreateTable(table)
+		if err != nil {
+			return err
+		}
+		statements = created
+	}
+	for _, statement := range statements {
+		err := database.Execute(statement)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Could not set up the history table!\n%s", err.Display()))
+		}
+	}
+	return nil
+}
+
+func recordHistory(database db.Database, dialect drivers.Dialect, entry HistoryEntry) errors.Error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	statements, err := json.Marshal(entry.Statements)
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	appliedSchema, err := json.Marshal(entry.Schema)
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	renames := entry.Renames
+	if renames == nil {
+		renames = []HistoryRename{}
+	}
+	appliedRenames, err := json.Marshal(renames)
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	columns := make([]string, 0, len(historyTable.Columns))
+	for _, column := range historyTable.Columns {
+		columns = append(columns, dialect.QuoteIdentifier(column.Name))
+	}
+	values := []string{
+		entry.AppliedAt.UTC().Format(historyTimeFormat),
+		entry.SchemaHash,
+		entry.ToolVersion,
+		entry.Status,
+		string(changes),
+		string(statements),
+		entry.Message,
+		string(appliedSchema),
+		string(appliedRenames),
+	}
+	for i, value := range values {
+		values[i] = dialect.QuoteLiteral(value)
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		dialect.QuoteIdentifier(HistoryTableName),
+		strings.Join(columns, ", "),
+		strings.Join(values, ", "),
+	)
+	return database.Execute(query)
+}
+
+// updateHistory records the status and message of an entry recorded as
+// pending. Entries are identified by their applied_at and schema hash.
+func updateHistory(database db.Database, dialect drivers.Dialect, entry HistoryEntry) errors.Error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s = %s, %s = %s WHERE %s = %s AND %s = %s",
+		dialect.QuoteIdentifier(HistoryTableName),
+		dialect.QuoteIdentifier("status"),
+		dialect.QuoteLiteral(entry.Status),
+		dialect.QuoteIdentifier("message"),
+		dialect.QuoteLiteral(entry.Message),
+		dialect.QuoteIdentifier("applied_at"),
+		dialect.QuoteLiteral(entry.AppliedAt.UTC().Format(historyTimeFormat)),
+		dialect.QuoteIdentifier("schema_hash"),
+		dialect.QuoteLiteral(entry.SchemaHash),
+	)
+	return database.Execute(query)
+}
+
+// readHistory returns the recorded entries of a database, oldest first.
+func readHistory(database db.Database, dialect drivers.Dialect) ([]HistoryEntry, errors.Error) {
+	if !hasHistoryTable(database) {
+		return []HistoryEntry{}, nil
+	}
+	columns := make([]string, 0, len(historyTable.Columns))
+	for _, column := range historyTable.Columns {
+		columns = append(columns, dialect.QuoteIdentifier(column.Name))
+	}
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s ORDER BY %s",
+		strings.Join(columns, ", "),
+		dialect.QuoteIdentifier(HistoryTableName),
+		dialect.QuoteIdentifier("applied_at"),
+	)
+	rows, err := database.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := make([]HistoryEntry, 0)
+	for rows.Next() {
+		var entry HistoryEntry
+		var appliedAt, changes, statements, appliedSchema string
+		var renames sql.NullString
+		if err := rows.Scan(&appliedAt, &entry.SchemaHash, &entry.ToolVersion, &entry.Status, &changes, &statements, &entry.Message, &appliedSchema, &renames); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		parsed, err := time.Parse(historyTimeFormat, appliedAt)
+		if err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		entry.AppliedAt = parsed
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if err := json.Unmarshal([]byte(statements), &entry.Statements); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if err := json.Unmarshal([]byte(appliedSchema), &entry.Schema); err != nil {
+			return nil, errors.NewUnexpectedError(err)
+		}
+		if renames.Valid {
+			if err := json.Unmarshal([]byte(renames.String), &entry.Renames); err != nil {
+				return nil, errors.NewUnexpectedError(err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewUnexpectedError(err)
+	}
+	return entries, nil
+}
+
+// lastApplied returns the last successful entry of a history, or nil.
+func lastApplied(entries []HistoryEntry) *HistoryEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Status == HistorySuccess {
+			return &entries[i]
+		}
+	}
+	return nil
+}
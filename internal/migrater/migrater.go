@@ -1,13 +1,450 @@
 package migrater
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/migrater/internal/db"
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
 )
 
 type Migrater interface {
 	Apply(db db.Database) errors.Error
 	Diff(db db.Database) (string, errors.Error)
-	Status(db db.Database) (string, errors.Error)
+	Status(db db.Database) (string, bool, errors.Error)
 	Plan(db db.Database) (string, errors.Error)
 }
+
+// Logger receives the progress of a migration, an operator.Operator can be
+// used directly.
+type Logger interface {
+	Write(string) errors.Error
+}
+
+// NewMigrater returns a Migrater bringing databases to the desired schema.
+// The desired schema can be nil when only the status of a database is
+// needed. The version is recorded in the history of applied migrations.
+// Destructive changes are only applied when allowDestructive is set or when
+// the desired schema allows them one by one.
+func NewMigrater(desired db.Database, version string, logger Logger, allowDestructive bool) Migrater {
+	return &migrater{
+		desired:          desired,
+		version:          version,
+		logger:           logger,
+		allowDestructive: allowDestructive,
+	}
+}
+
+type migrater struct {
+	desired          db.Database
+	version          string
+	logger           Logger
+	allowDestructive bool
+}
+
+func getDialect(database db.Database) (drivers.Dialect, errors.Error) {
+	driver := drivers.GetDriver(database.GetDriverType())
+	if driver == nil {
+		return nil, errors.New(fmt.Sprintf("Invalid driver: %s", database.GetDriverType()))
+	}
+	return driver, nil
+}
+
+func (m *migrater) changes(database db.Database) (ChangeSet, errors.Error) {
+	if m.desired == nil {
+		return nil, errors.New("No desired schema to compare with")
+	}
+	dialect, err := getDialect(database)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := readHistory(database, dialect)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := drivers.ResolveTypes(dialect, withoutAppliedRenames(withoutHistoryTable(m.desired.GetTables()), entries))
+	if err != nil {
+		return nil, err
+	}
+	live := drivers.NormalizeTypes(dialect, withoutHistoryTable(database.GetTables()))
+	// Dialects rebuilding tables do not check references when a table is
+	// created, cycles need not be split.
+	_, rebuilds := dialect.(drivers.TableRebuilder)
+	return orderTables(Compare(desired, live), !rebuilds), nil
+}
+
+// refusedChanges returns the destructive changes that are neither allowed by
+// the flag nor listed in the allow_destructive list of the desired schema.
+func (m *migrater) refusedChanges(changes ChangeSet) ChangeSet {
+	if m.allowDestructive {
+		return ChangeSet{}
+	}
+	allowed := m.desired.GetAllowedDestructiveChanges()
+	refused := make(ChangeSet, 0)
+	for _, change := range changes.DestructiveChanges() {
+		if !slices.Contains(allowed, change.String()) {
+			refused = append(refused, change)
+		}
+	}
+	return refused
+}
+
+// destructiveMessage lists refused changes in the form expected by the
+// allow_destructive list.
+func destructiveMessage(refused ChangeSet) string {
+	lines := make([]string, 0, len(refused))
+	for _, change := range refused {
+		lines = append(lines, fmt.Sprintf("  %q", change.String()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *migrater) statements(database db.Database, changes ChangeSet) ([]Statement, errors.Error) {
+	dialect, err := getDialect(database)
+	if err != nil {
+		return nil, err
+	}
+	return planStatements(dialect, changes)
+}
+
+func (m *migrater) Diff(database db.Database) (string, errors.Error) {
+	changes, err := m.changes(database)
+	if err != nil {
+		return "", err
+	}
+	return changes.String(), nil
+}
+
+func (m *migrater) Plan(database db.Database) (string, errors.Error) {
+	changes, err := m.changes(database)
+	if err != nil {
+		return "", err
+	}
+	statements, err := m.statements(database, changes)
+	if err != nil {
+		return "", err
+	}
+	header := fmt.Sprintf("-- Migration plan for database %s (%s), migrater %s", database.GetName(), database.GetDriverType(), m.version)
+	if len(statements) == 0 {
+		return header + "\n-- No changes.", nil
+	}
+	if refused := m.refusedChanges(changes); !refused.IsEmpty() {
+		header += "\n-- Destructive changes, not applied without --allow-destructive or allow_destructive:"
+		for _, change := range refused {
+			header += fmt.Sprintf("\n--   %q", change.String())
+		}
+	}
+	if cycles := changes.Cycles(); !cycles.IsEmpty() {
+		header += "\n-- Foreign key cycles, split to create or drop their tables:"
+		for _, change := range cycles {
+			header += fmt.Sprintf("\n--   %s: %s", cycleString(change.Cycle), change)
+		}
+	}
+	lines := make([]string, 0, len(statements)+1)
+	lines = append(lines, header)
+	for _, statement := range statements {
+		if statement.Target() == "" {
+			lines = append(lines, statement.String())
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("-- %s\n%s", statement.Target(), statement))
+	}
+	return strings.Join(lines, "\n\n"), nil
+}
+
+// Status reports when the database was last migrated and whether it still
+// matches the schema applied at that time. When a desired schema is given,
+// it also reports the changes still pending. drifted is set when the
+// database no longer matches the applied schema or the last migration
+// failed or did not complete.
+func (m *migrater) Status(database db.Database) (status string, drifted bool, err errors.Error) {
+	dialect, err := getDialect(database)
+	if err != nil {
+		return "", false, err
+	}
+	entries, err := readHistory(database, dialect)
+	if err != nil {
+		return "", false, err
+	}
+	status = fmt.Sprintf("Database: %s\nDriver: %s\n", database.GetName(), database.GetDriverType())
+	last := lastApplied(entries)
+	if last == nil {
+		status += "Never migrated.\n"
+	} else {
+		status += fmt.Sprintf(
+			"Last migrated: %s with migrater %s (%d statement(s), schema %s)\n",
+			last.AppliedAt.Format(time.RFC3339),
+			last.ToolVersion,
+			len(last.Statements),
+			last.SchemaHash,
+		)
+		applied, err := drivers.ResolveTypes(dialect, last.Schema)
+		if err != nil {
+			return "", false, err
+		}
+		drift := Compare(applied, drivers.NormalizeTypes(dialect, withoutHistoryTable(database.GetTables())))
+		if drift.IsEmpty() {
+			status += "The database matches the last applied schema.\n"
+		} else {
+			drifted = true
+			status += fmt.Sprintf("The database has drifted from the last applied schema:\n%s\n", drift)
+		}
+	}
+	if len(entries) > 0 && entries[len(entries)-1].Status == HistoryFailed {
+		drifted = true
+		failed := entries[len(entries)-1]
+		status += fmt.Sprintf("Last migration failed: %s\n%s\n", failed.AppliedAt.Format(time.RFC3339), failed.Message)
+	}
+	if len(entries) > 0 && entries[len(entries)-1].Status == HistoryPending {
+		drifted = true
+		pending := entries[len(entries)-1]
+		status += fmt.Sprintf("Last migration did not complete: %s, some of its statements may have been applied.\n", pending.AppliedAt.Format(time.RFC3339))
+	}
+	if m.desired != nil {
+		changes, err := m.changes(database)
+		if err != nil {
+			return "", false, err
+		}
+		if changes.IsEmpty() {
+			status += "The database is up to date with the desired schema.\n"
+		} else {
+			status += fmt.Sprintf("%d pending change(s):\n%s\n", len(changes), changes)
+		}
+	}
+	return strings.TrimSuffix(status, "\n"), drifted, nil
+}
+
+// Apply executes the changes needed to bring the database to the desired
+// schema and records them in the history table.
+func (m *migrater) Apply(database db.Database) errors.Error {
+	changes, err := m.changes(database)
+	if err != nil {
+		return err
+	}
+	if refused := m.refusedChanges(changes); !refused.IsEmpty() {
+		return errors.New(fmt.Sprintf(
+			"Refusing to apply destructive changes, run with --allow-destructive or add them to the allow_destructive list of the schema:\n%s",
+			destructiveMessage(refused),
+		))
+	}
+	statements, err := m.statements(database, changes)
+	if err != nil {
+		return err
+	}
+	dialect, err := getDialect(database)
+	if err != nil {
+		return err
+	}
+	desired := withoutHistoryTable(m.desired.GetTables())
+	hash, err := hashSchema(desired)
+	if err != nil {
+		return err
+	}
+	entries, err := readHistory(database, dialect)
+	if err != nil {
+		return err
+	}
+	last := lastApplied(entries)
+	if len(statements) == 0 && last != nil && last.SchemaHash == hash {
+		return m.log("Nothing to migrate.")
+	}
+	err = ensureHistoryTable(database, dialect)
+	if err != nil {
+		return err
+	}
+	entry := HistoryEntry{
+		AppliedAt:   time.Now(),
+		SchemaHash:  hash,
+		ToolVersion: m.version,
+		Status:      HistorySuccess,
+		Changes:     make([]string, 0, len(changes)),
+		Statements:  make([]string, 0, len(statements)),
+		Schema:      desired,
+		Renames:     historyRenames(changes),
+	}
+	for _, change := range changes {
+		entry.Changes = append(entry.Changes, change.String())
+	}
+	for _, statement := range statements {
+		entry.Statements = append(entry.Statements, statement.String())
+	}
+	if dialect.SupportsTransactionalDDL() {
+		return m.applyInTransaction(database, dialect, statements, entry)
+	}
+	return m.applyWithCheckpoints(database, dialect, statements, entry)
+}
+
+// applyInTransaction executes the statements and records the history entry
+// in a single transaction, which is rolled back if any statement fails.
+// Statements which have no effect within a transaction run around it.
+func (m *migrater) applyInTransaction(database db.Database, dialect drivers.Dialect, statements []Statement, entry HistoryEntry) errors.Error {
+	before, statements, after := splitNoTransaction(statements)
+	for _, statement := range before {
+		if err := m.execute(database, statement); err != nil {
+			return m.recordFailure(database, dialect, entry, errors.New(fmt.Sprintf("%s\nNo changes were applied.", err.Display())))
+		}
+	}
+	err := m.transaction(database, dialect, statements, entry)
+	for _, statement := range after {
+		if afterErr := m.execute(database, statement); afterErr != nil {
+			if err != nil {
+				return errors.New(fmt.Sprintf("%s\n%s", err.Display(), afterErr.Display()))
+			}
+			return afterErr
+		}
+	}
+	return err
+}
+
+// splitNoTransaction splits the statements running before the migration
+// transaction and after it from the others.
+func splitNoTransaction(statements []Statement) (before []Statement, inner []Statement, after []Statement) {
+	start := 0
+	for start < len(statements) && statements[start].NoTransaction {
+		start++
+	}
+	end := len(statements)
+	for end > start && statements[end-1].NoTransaction {
+		end--
+	}
+	return statements[:start], statements[start:end], statements[end:]
+}
+
+// execute logs and executes a single statement outside of a transaction.
+func (m *migrater) execute(database db.Database, statement Statement) errors.Error {
+	err := m.log(statement.String())
+	if err != nil {
+		return err
+	}
+	err = executeStatement(database, statement)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Migration failed on: %s\n%s", statement, err.Display()))
+	}
+	return nil
+}
+
+// executeStatement executes a statement, a foreign key check fails when it
+// returns rows.
+func executeStatement(database db.Database, statement Statement) errors.Error {
+	if !statement.ForeignKeyCheck {
+		return database.Execute(statement.SQL)
+	}
+	rows, err := database.Query(statement.SQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	violations := 0
+	for rows.Next() {
+		violations++
+	}
+	if err := rows.Err(); err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	if violations > 0 {
+		return errors.New(fmt.Sprintf("%d rows of %s violate its foreign keys", violations, statement.Table))
+	}
+	return nil
+}
+
+// transaction executes the statements and records the history entry in a
+// single transaction.
+func (m *migrater) transaction(database db.Database, dialect drivers.Dialect, statements []Statement, entry HistoryEntry) errors.Error {
+	err := database.Begin()
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		err = m.log(statement.String())
+		if err == nil {
+			err = executeStatement(database, statement)
+			if err != nil {
+				err = errors.New(fmt.Sprintf("Migration failed on: %s\n%s", statement, err.Display()))
+			}
+		}
+		if err != nil {
+			if rollbackErr := database.Rollback(); rollbackErr != nil {
+				return errors.New(fmt.Sprintf("%s\nCould not rollback the migration: %s", err.Display(), rollbackErr.Display()))
+			}
+			err = errors.New(fmt.Sprintf("%s\nThe migration was rolled back, no changes were applied.", err.Display()))
+			return m.recordFailure(database, dialect, entry, err)
+		}
+	}
+	err = recordHistory(database, dialect, entry)
+	if err != nil {
+		if rollbackErr := database.Rollback(); rollbackErr != nil {
+			return errors.New(fmt.Sprintf("%s\nCould not rollback the migration: %s", err.Display(), rollbackErr.Display()))
+		}
+		return err
+	}
+	return database.Commit()
+}
+
+// applyWithCheckpoints executes the statements one by one for drivers where
+// DDL statements are committed as soon as they run. On failure it reports
+// which statements were applied and which were not. The entry is recorded
+// as pending before the first statement runs and completed afterwards, so
+// that a migration interrupted midway is left in the history.
+func (m *migrater) applyWithCheckpoints(database db.Database, dialect drivers.Dialect, statements []Statement, entry HistoryEntry) errors.Error {
+	err := m.log(fmt.Sprintf("Warning: %s commits DDL statements immediately, a failed migration cannot be rolled back.", database.GetDriverType()))
+	if err != nil {
+		return err
+	}
+	status := entry.Status
+	entry.Status = HistoryPending
+	err = recordHistory(database, dialect, entry)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Could not record the migration in the history!\n%s\nNo statement was applied.", err.Display()))
+	}
+	for i, statement := range statements {
+		err = m.log(fmt.Sprintf("[%d/%d] %s", i+1, len(statements), statement))
+		if err != nil {
+			return err
+		}
+		err = executeStatement(database, statement)
+		if err != nil {
+			message := fmt.Sprintf("Migration failed on statement %d/%d: %s\n%s\n", i+1, len(statements), statement, err.Display())
+			if i > 0 {
+				message += fmt.Sprintf("Statements 1 to %d were applied and committed.\n", i)
+			} else {
+				message += "No statement was applied.\n"
+			}
+			if i+1 < len(statements) {
+				message += fmt.Sprintf("Statements %d to %d were not run.", i+2, len(statements))
+			}
+			entry.Status = HistoryFailed
+			entry.Message = strings.TrimSuffix(message, "\n")
+			if updateErr := updateHistory(database, dialect, entry); updateErr != nil {
+				return errors.New(fmt.Sprintf("%s\nCould not record the failure in the history, the migration is left %s: %s", entry.Message, HistoryPending, updateErr.Display()))
+			}
+			return errors.New(entry.Message)
+		}
+	}
+	entry.Status = status
+	err = updateHistory(database, dialect, entry)
+	if err != nil {
+		return errors.New(fmt.Sprintf("All statements were applied and committed, but the migration is left %s in the history: %s", HistoryPending, err.Display()))
+	}
+	return nil
+}
+
+// recordFailure records a failed migration in the history and returns the
+// error that caused it.
+func (m *migrater) recordFailure(database db.Database, dialect drivers.Dialect, entry HistoryEntry, err errors.Error) errors.Error {
+	entry.Status = HistoryFailed
+	entry.Message = err.Display()
+	if recordErr := recordHistory(database, dialect, entry); recordErr != nil {
+		return errors.New(fmt.Sprintf("%s\nCould not record the failure in the history: %s", err.Display(), recordErr.Display()))
+	}
+	return err
+}
+
+func (m *migrater) log(message string) errors.Error {
+	if m.logger == nil {
+		return nil
+	}
+	return m.logger.Write(message)
+}
@@ -0,0 +1,513 @@
+package migrater
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/db"
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// fakeDatabase records executed statements and transactions instead of
+// running them. Statements starting with failOn return an error, queries
+// return rowCount empty rows followed by rowsErr. openRows counts the rows
+// not closed yet.
+type fakeDatabase struct {
+	*db.SqlDatabase
+	executed []string
+	failOn   string
+	rowCount int
+	rowsErr  error
+	openRows int
+}
+
+type fakeRows struct {
+	database *fakeDatabase
+	count    int
+}
+
+func (r *fakeRows) Next() bool {
+	r.count--
+	return r.count >= 0
+}
+
+func (r *fakeRows) Scan(...any) error {
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return r.database.rowsErr
+}
+
+func (r *fakeRows) Close() error {
+	r.database.openRows--
+	return nil
+}
+
+func (f *fakeDatabase) Query(query string) (drivers.Result, errors.Error) {
+	f.executed = append(f.executed, query)
+	f.openRows++
+	return &fakeRows{database: f, count: f.rowCount}, nil
+}
+
+func (f *fakeDatabase) Execute(query string) errors.Error {
+	f.executed = append(f.executed, query)
+	if f.failOn != "" && strings.HasPrefix(query, f.failOn) {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func (f *fakeDatabase) Begin() errors.Error {
+	f.executed = append(f.executed, "BEGIN")
+	return nil
+}
+
+func (f *fakeDatabase) Commit() errors.Error {
+	f.executed = append(f.executed, "COMMIT")
+	return nil
+}
+
+func (f *fakeDatabase) Rollback() errors.Error {
+	f.executed = append(f.executed, "ROLLBACK")
+	return nil
+}
+
+func newFakeDatabase(tables ...schema.Table) *fakeDatabase {
+	return &fakeDatabase{SqlDatabase: &db.SqlDatabase{Name: "test", DriverType: "sqlite", Tables: tables}}
+}
+
+// assertExecuted checks that each executed statement starts with the
+// expected prefix.
+func assertExecuted(t *testing.T, executed []string, want []string) {
+	t.Helper()
+	if len(executed) != len(want) {
+		t.Fatalf("executed %q, want %q", executed, want)
+	}
+	for i := range want {
+		if !strings.HasPrefix(executed[i], want[i]) {
+			t.Errorf("statement %d = %q, want %q", i, executed[i], want[i])
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	desired := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "email", Type: "TEXT"},
+		},
+	})
+	live := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+		},
+	})
+	plan, err := NewMigrater(desired, "test", nil, false).Plan(live)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "-- Migration plan for database test (sqlite), migrater test\n\n" +
+		"-- users.email\nALTER TABLE \"users\" ADD COLUMN \"email\" TEXT;"
+	if plan != want {
+		t.Errorf("Plan() = %q, want %q", plan, want)
+	}
+	if len(live.executed) != 0 {
+		t.Errorf("Plan() executed statements: %v", live.executed)
+	}
+}
+
+func TestPlanLogicalTypes(t *testing.T) {
+	desired := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "int64"},
+			{Name: "email", Type: "string(255)"},
+			{Name: "active", Type: "bool"},
+		},
+	})
+	live := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "email", Type: "VARCHAR(255)"},
+		},
+	})
+	plan, err := NewMigrater(desired, "test", nil, false).Plan(live)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "-- Migration plan for database test (sqlite), migrater test\n\n" +
+		"-- users.active\nALTER TABLE \"users\" ADD COLUMN \"active\" BOOLEAN;"
+	if plan != want {
+		t.Errorf("Plan() = %q, want %q", plan, want)
+	}
+}
+
+func TestPlanNormalizesTypes(t *testing.T) {
+	desired := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "integer"},
+			{Name: "email", Type: "character varying(255)"},
+			{Name: "balance", Type: "decimal(10, 2)"},
+		},
+	})
+	live := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INT4"},
+			{Name: "email", Type: "VARCHAR(255)"},
+			{Name: "balance", Type: "NUMERIC(10,2)"},
+		},
+	})
+	live.DriverType = drivers.PostgresDriverType
+	plan, err := NewMigrater(desired, "test", nil, false).Plan(live)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "-- Migration plan for database test (postgres), migrater test\n-- No changes."
+	if plan != want {
+		t.Errorf("Plan() = %q, want %q", plan, want)
+	}
+}
+
+func TestPlanRebuild(t *testing.T) {
+	desired := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "age", Type: "INTEGER"},
+			{Name: "active", Type: "BOOLEAN", Constraints: schema.Constraints{schema.DefaultConstraint{Value: "1"}}},
+		},
+		Indexes: []schema.Index{{Name: "users_age_idx", Columns: []string{"age"}}},
+	})
+	live := newFakeDatabase(schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "age", Type: "TEXT"},
+		},
+	})
+	plan, err := NewMigrater(desired, "test", nil, true).Plan(live)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "-- Migration plan for database test (sqlite), migrater test\n\n" +
+		"PRAGMA foreign_keys = OFF;\n\n" +
+		"-- users\nCREATE TABLE \"new_users\" (\n  \"id\" INTEGER,\n  \"age\" INTEGER,\n  \"active\" BOOLEAN DEFAULT 1\n);\n\n" +
+		"-- users\nINSERT INTO \"new_users\" (\"id\", \"age\") SELECT \"id\", \"age\" FROM \"users\";\n\n" +
+		"-- users\nDROP TABLE \"users\";\n\n" +
+		"-- users\nALTER TABLE \"new_users\" RENAME TO \"users\";\n\n" +
+		"-- users\nCREATE INDEX \"users_age_idx\" ON \"users\" (\"age\");\n\n" +
+		"-- users\nPRAGMA foreign_key_check(\"users\");\n\n" +
+		"PRAGMA foreign_keys = ON;"
+	if plan != want {
+		t.Errorf("Plan() = %q, want %q", plan, want)
+	}
+}
+
+func TestPlanForeignKeyCycle(t *testing.T) {
+	desired := newFakeDatabase(
+		schema.Table{Name: "teams", Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "owner_id", Type: "INTEGER", Constraints: schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: "members", ReferencedColumn: "id"}}},
+		}},
+		schema.Table{Name: "members", Columns: []schema.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "team_id", Type: "INTEGER", Constraints: schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: "teams", ReferencedColumn: "id"}}},
+		}},
+	)
+	live := newFakeDatabase()
+	live.DriverType = drivers.PostgresDriverType
+	plan, err := NewMigrater(desired, "test", nil, false).Plan(live)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "-- Migration plan for database test (postgres), migrater test\n" +
+		"-- Foreign key cycles, split to create or drop their tables:\n" +
+		"--   teams -> members -> teams: + constraint ForeignKey(teams.id) on members.team_id\n\n" +
+		"-- members\nCREATE TABLE \"members\" (\n  \"id\" INTEGER,\n  \"team_id\" INTEGER\n);\n\n" +
+		"-- teams\nCREATE TABLE \"teams\" (\n  \"id\" INTEGER,\n  \"owner_id\" INTEGER,\n" +
+		"  CONSTRAINT \"teams_owner_id_fkey\" FOREIGN KEY (\"owner_id\") REFERENCES \"members\" (\"id\")\n);\n\n" +
+		"-- members.team_id\nALTER TABLE \"members\" ADD CONSTRAINT \"members_team_id_fkey\" FOREIGN KEY (\"team_id\") REFERENCES \"teams\" (\"id\");"
+	if plan != want {
+		t.Errorf("Plan() = %q, want %q", plan, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	users := schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}
+	posts := schema.Table{Name: "posts", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}
+	tests := []struct {
+		name    string
+		driver  string
+		failOn  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "transaction",
+			driver: "sqlite",
+			want: []string{
+				"CREATE TABLE \"migrater_history\"",
+				"BEGIN",
+				"CREATE TABLE \"users\" (\n  \"id\" INTEGER\n)",
+				"DROP TABLE \"posts\"",
+				"INSERT INTO \"migrater_history\"",
+				"COMMIT",
+			},
+		},
+		{
+			name:   "rollback",
+			driver: "sqlite",
+			failOn: "DROP TABLE",
+			want: []string{
+				"CREATE TABLE \"migrater_history\"",
+				"BEGIN",
+				"CREATE TABLE \"users\"",
+				"DROP TABLE \"posts\"",
+				"ROLLBACK",
+				"INSERT INTO \"migrater_history\"",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "checkpoints",
+			driver: "mysql",
+			failOn: "DROP TABLE",
+			want: []string{
+				"CREATE TABLE `migrater_history`",
+				"INSERT INTO `migrater_history`",
+				"CREATE TABLE `users`",
+				"DROP TABLE `posts`",
+				"UPDATE `migrater_history` SET `status` = 'failed'",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "checkpoints applied",
+			driver: "mysql",
+			want: []string{
+				"CREATE TABLE `migrater_history`",
+				"INSERT INTO `migrater_history`",
+				"CREATE TABLE `users`",
+				"DROP TABLE `posts`",
+				"UPDATE `migrater_history` SET `status` = 'success'",
+			},
+		},
+		{
+			name:   "checkpoints history left pending",
+			driver: "mysql",
+			failOn: "UPDATE",
+			want: []string{
+				"CREATE TABLE `migrater_history`",
+				"INSERT INTO `migrater_history`",
+				"CREATE TABLE `users`",
+				"DROP TABLE `posts`",
+				"UPDATE `migrater_history`",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "checkpoints history not recorded",
+			driver: "mysql",
+			failOn: "INSERT",
+			want: []string{
+				"CREATE TABLE `migrater_history`",
+				"INSERT INTO `migrater_history`",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newFakeDatabase(users)
+			live := newFakeDatabase(posts)
+			live.DriverType = drivers.DriverType(tt.driver)
+			live.failOn = tt.failOn
+			err := NewMigrater(desired, "test", nil, true).Apply(live)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assertExecuted(t, live.executed, tt.want)
+		})
+	}
+}
+
+func TestApplyDestructiveChanges(t *testing.T) {
+	users := schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}
+	posts := schema.Table{Name: "posts", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}
+	tests := []struct {
+		name             string
+		allowDestructive bool
+		allowed          []string
+		wantErr          bool
+	}{
+		{name: "refused", wantErr: true},
+		{name: "flag", allowDestructive: true},
+		{name: "allow list", allowed: []string{"- table posts"}},
+		{name: "other change allowed", allowed: []string{"- table comments"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newFakeDatabase(users)
+			desired.AllowDestructive = tt.allowed
+			live := newFakeDatabase(users, posts)
+			err := NewMigrater(desired, "test", nil, tt.allowDestructive).Apply(live)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Display(), `"- table posts"`) {
+					t.Errorf("Apply() error = %q, want the refused change", err.Display())
+				}
+				if len(live.executed) != 0 {
+					t.Errorf("Apply() executed statements: %v", live.executed)
+				}
+			} else {
+				assertExecuted(t, live.executed, []string{
+					"CREATE TABLE \"migrater_history\"",
+					"BEGIN",
+					"DROP TABLE \"posts\"",
+					"INSERT INTO \"migrater_history\"",
+					"COMMIT",
+				})
+			}
+		})
+	}
+}
+
+func TestApplyRebuild(t *testing.T) {
+	desired := schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}, {Name: "age", Type: "INTEGER"}}}
+	live := schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}, {Name: "age", Type: "TEXT"}}}
+	tests := []struct {
+		name       string
+		violations int
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "rebuild",
+			want: []string{
+				"CREATE TABLE \"migrater_history\"",
+				"PRAGMA foreign_keys = OFF",
+				"BEGIN",
+				"CREATE TABLE \"new_users\"",
+				"INSERT INTO \"new_users\"",
+				"DROP TABLE \"users\"",
+				"ALTER TABLE \"new_users\" RENAME TO \"users\"",
+				"PRAGMA foreign_key_check(\"users\")",
+				"INSERT INTO \"migrater_history\"",
+				"COMMIT",
+				"PRAGMA foreign_keys = ON",
+			},
+		},
+		{
+			name:       "foreign key violations",
+			violations: 2,
+			want: []string{
+				"CREATE TABLE \"migrater_history\"",
+				"PRAGMA foreign_keys = OFF",
+				"BEGIN",
+				"CREATE TABLE \"new_users\"",
+				"INSERT INTO \"new_users\"",
+				"DROP TABLE \"users\"",
+				"ALTER TABLE \"new_users\" RENAME TO \"users\"",
+				"PRAGMA foreign_key_check(\"users\")",
+				"ROLLBACK",
+				"INSERT INTO \"migrater_history\"",
+				"PRAGMA foreign_keys = ON",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := newFakeDatabase(live)
+			database.rowCount = tt.violations
+			err := NewMigrater(newFakeDatabase(desired), "test", nil, true).Apply(database)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Display(), "2 rows of users violate its foreign keys") {
+				t.Errorf("Apply() error = %q, want the violations", err.Display())
+			}
+			assertExecuted(t, database.executed, tt.want)
+			if database.openRows != 0 {
+				t.Errorf("%d rows left open", database.openRows)
+			}
+		})
+	}
+}
+
+func TestReadHistoryClosesRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		rowCount int
+		rowsErr  error
+		wantErr  bool
+	}{
+		{name: "empty history"},
+		{name: "unreadable entry", rowCount: 1, wantErr: true},
+		{name: "interrupted rows", rowsErr: io.ErrUnexpectedEOF, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := newFakeDatabase(historyTable)
+			database.rowCount = tt.rowCount
+			database.rowsErr = tt.rowsErr
+			_, err := readHistory(database, drivers.GetDriver(drivers.SqliteDriverType))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readHistory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if database.openRows != 0 {
+				t.Errorf("%d rows left open", database.openRows)
+			}
+		})
+	}
+}
+
+func TestEnsureHistoryTableTextColumns(t *testing.T) {
+	tests := []struct {
+		driverType drivers.DriverType
+		want       string
+	}{
+		{driverType: drivers.MysqlDriverType, want: "`changes` LONGTEXT NOT NULL"},
+		{driverType: drivers.MariadbDriverType, want: "`changes` LONGTEXT NOT NULL"},
+		{driverType: drivers.PostgresDriverType, want: "\"changes\" TEXT NOT NULL"},
+		{driverType: drivers.SqlserverDriverType, want: "[changes] NVARCHAR(MAX) NOT NULL"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.driverType), func(t *testing.T) {
+			database := newFakeDatabase()
+			if err := ensureHistoryTable(database, drivers.GetDriver(tt.driverType)); err != nil {
+				t.Fatalf("ensureHistoryTable() error = %v", err)
+			}
+			if len(database.executed) != 1 || !strings.Contains(database.executed[0], tt.want) {
+				t.Errorf("executed %q, want %q", database.executed, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureHistoryTableAddsColumns(t *testing.T) {
+	previous := historyTable
+	previous.Columns = previous.Columns[:len(previous.Columns)-1]
+	database := newFakeDatabase(previous)
+	if err := ensureHistoryTable(database, drivers.GetDriver(drivers.SqliteDriverType)); err != nil {
+		t.Fatalf("ensureHistoryTable() error = %v", err)
+	}
+	assertExecuted(t, database.executed, []string{"ALTER TABLE \"migrater_history\" ADD COLUMN \"renames\" TEXT"})
+
+	database = newFakeDatabase(historyTable)
+	if err := ensureHistoryTable(database, drivers.GetDriver(drivers.SqliteDriverType)); err != nil {
+		t.Fatalf("ensureHistoryTable() error = %v", err)
+	}
+	assertExecuted(t, database.executed, []string{})
+}
@@ -0,0 +1,247 @@
+package migrater
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// dependencyGraph holds the tables a table references through its foreign
+// keys, among the tables created, or dropped, by a migration. Views are not
+// part of the schema and indexes depend on their table only, which the
+// order of the change kinds already takes care of.
+type dependencyGraph struct {
+	tables     []schema.Table
+	references map[string][]string
+}
+
+func newDependencyGraph(tables []schema.Table) dependencyGraph {
+	names := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		names[table.Name] = true
+	}
+	graph := dependencyGraph{tables: tables, references: make(map[string][]string, len(tables))}
+	for _, table := range tables {
+		for _, referenced := range referencedTables(table) {
+			if referenced != table.Name && names[referenced] && !slices.Contains(graph.references[table.Name], referenced) {
+				graph.references[table.Name] = append(graph.references[table.Name], referenced)
+			}
+		}
+	}
+	return graph
+}
+
+// referencedTables returns the tables referenced by the foreign keys of a
+// table, in the order they are declared.
+func referencedTables(table schema.Table) []string {
+	var referenced []string
+	for _, column := range table.Columns {
+		for _, constraint := range column.Constraints {
+			if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+				referenced = append(referenced, fk.ReferencedTable)
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Type == schema.ForeignKeyConstraintType {
+			referenced = append(referenced, constraint.ReferencedTable)
+		}
+	}
+	return referenced
+}
+
+// cycleEdge is a reference of a table to another one removed from the graph
+// to break the cycle it closes.
+type cycleEdge struct {
+	table      string
+	referenced string
+	cycle      []string
+}
+
+// reversed returns the graph of the tables referencing each table, where a
+// table comes before the tables it references.
+func (g dependencyGraph) reversed() dependencyGraph {
+	reversed := dependencyGraph{tables: g.tables, references: make(map[string][]string, len(g.tables))}
+	for _, table := range g.tables {
+		for _, referenced := range g.references[table.Name] {
+			reversed.references[referenced] = append(reversed.references[referenced], table.Name)
+		}
+	}
+	return reversed
+}
+
+// order returns the tables so that every table comes after the tables it
+// references, keeping their order otherwise. With split set, a cycle is
+// broken by removing the reference closing it, starting from the first
+// table left, and sorting goes on. Otherwise the tables of cycles are left
+// in their order at the end.
+func (g dependencyGraph) order(split bool) ([]schema.Table, []cycleEdge) {
+	sorted := make([]schema.Table, 0, len(g.tables))
+	done := make(map[string]bool, len(g.tables))
+	removed := make(map[[2]string]bool)
+	var edges []cycleEdge
+	for len(sorted) < len(g.tables) {
+		ready := slices.IndexFunc(g.tables, func(table schema.Table) bool {
+			if done[table.Name] {
+				return false
+			}
+			for _, referenced := range g.references[table.Name] {
+				if !done[referenced] && !removed[[2]string{table.Name, referenced}] {
+					return false
+				}
+			}
+			return true
+		})
+		if ready != -1 {
+			sorted = append(sorted, g.tables[ready])
+			done[g.tables[ready].Name] = true
+			continue
+		}
+		if !split {
+			for _, table := range g.tables {
+				if !done[table.Name] {
+					sorted = append(sorted, table)
+				}
+			}
+			break
+		}
+		edge := g.findCycle(done, removed)
+		removed[[2]string{edge.table, edge.referenced}] = true
+		edges = append(edges, edge)
+	}
+	return sorted, edges
+}
+
+// findCycle follows the references left from the first table left until a
+// table is reached again, and returns the reference closing the cycle.
+// Every table left is part of a cycle or references one.
+func (g dependencyGraph) findCycle(done map[string]bool, removed map[[2]string]bool) cycleEdge {
+	var path []string
+	for _, table := range g.tables {
+		if !done[table.Name] {
+			path = append(path, table.Name)
+			break
+		}
+	}
+	for {
+		current := path[len(path)-1]
+		var next string
+		for _, referenced := range g.references[current] {
+			if !done[referenced] && !removed[[2]string{current, referenced}] {
+				next = referenced
+				break
+			}
+		}
+		if start := slices.Index(path, next); start != -1 {
+			return cycleEdge{table: current, referenced: next, cycle: append(slices.Clone(path[start:]), next)}
+		}
+		path = append(path, next)
+	}
+}
+
+// orderTables orders the tables created and dropped by the changes along
+// their foreign keys: a table is created after the tables it references
+// and dropped before them. With split set, the foreign keys closing a
+// cycle are added once the tables of the cycle are created, and dropped
+// before they are dropped, the changes doing so holding the cycle. Dialects
+// which do not check references when a table is created, and cannot add
+// foreign keys to existing tables, leave split unset.
+func orderTables(changes ChangeSet, split bool) ChangeSet {
+	var added, dropped []schema.Table
+	for _, change := range changes {
+		switch change.Kind {
+		case AddTableChange:
+			added = append(added, change.Table)
+		case DropTableChange:
+			dropped = append(dropped, change.Table)
+		}
+	}
+	if len(added) < 2 && len(dropped) < 2 {
+		return changes
+	}
+	created, createEdges := newDependencyGraph(added).order(split)
+	removed, dropEdges := newDependencyGraph(dropped).reversed().order(split)
+	// The edges of the reversed graph go from a referenced table to the
+	// table whose foreign key closes the cycle.
+	for i, edge := range dropEdges {
+		slices.Reverse(edge.cycle)
+		dropEdges[i] = cycleEdge{table: edge.referenced, referenced: edge.table, cycle: edge.cycle}
+	}
+
+	ordered := make(ChangeSet, 0, len(changes))
+	for _, change := range changes {
+		switch change.Kind {
+		case AddTableChange:
+			table := created[0]
+			created = created[1:]
+			var deferred ChangeSet
+			table, deferred = splitForeignKeys(table, createEdges, AddConstraintChange, AddTableConstraintChange)
+			ordered = append(ordered, Change{Kind: AddTableChange, Table: table})
+			ordered = append(ordered, deferred...)
+		case DropTableChange:
+			table := removed[0]
+			removed = removed[1:]
+			_, deferred := splitForeignKeys(table, dropEdges, DropConstraintChange, DropTableConstraintChange)
+			ordered = append(ordered, Change{Kind: DropTableChange, Table: table})
+			ordered = append(ordered, deferred...)
+		default:
+			ordered = append(ordered, change)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return changeRank(ordered[i].Kind) < changeRank(ordered[j].Kind)
+	})
+	return ordered
+}
+
+// splitForeignKeys removes the foreign keys of a table closing a cycle, and
+// returns the changes adding or dropping them on their own.
+func splitForeignKeys(table schema.Table, edges []cycleEdge, columnKind ChangeKind, tableKind ChangeKind) (schema.Table, ChangeSet) {
+	cycleOf := func(referenced string) []string {
+		for _, edge := range edges {
+			if edge.table == table.Name && edge.referenced == referenced {
+				return edge.cycle
+			}
+		}
+		return nil
+	}
+	var changes ChangeSet
+	columns := make([]schema.Column, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		constraints := make(schema.Constraints, 0, len(column.Constraints))
+		for _, constraint := range column.Constraints {
+			if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+				if cycle := cycleOf(fk.ReferencedTable); cycle != nil {
+					changes = append(changes, Change{Kind: columnKind, Table: table, Column: column, Constraint: fk, Cycle: cycle})
+					continue
+				}
+			}
+			constraints = append(constraints, constraint)
+		}
+		column.Constraints = constraints
+		columns = append(columns, column)
+	}
+	constraints := make([]schema.TableConstraint, 0, len(table.Constraints))
+	for _, constraint := range table.Constraints {
+		if constraint.Type == schema.ForeignKeyConstraintType {
+			if cycle := cycleOf(constraint.ReferencedTable); cycle != nil {
+				changes = append(changes, Change{Kind: tableKind, Table: table, TableConstraint: constraint, Cycle: cycle})
+				continue
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	if len(changes) == 0 {
+		return table, nil
+	}
+	table.Columns = columns
+	table.Constraints = constraints
+	return table, changes
+}
+
+// cycleString renders a foreign key cycle, e.g. users -> posts -> users.
+func cycleString(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
@@ -0,0 +1,96 @@
+package migrater
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+func TestOrderTables(t *testing.T) {
+	references := func(table string) schema.Constraints {
+		return schema.Constraints{schema.ForeignKeyConstraint{ReferencedTable: table, ReferencedColumn: "id"}}
+	}
+	users := schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}}}
+	posts := schema.Table{Name: "posts", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}, {Name: "user_id", Type: "INTEGER", Constraints: references("users")}}}
+	comments := schema.Table{Name: "comments", Columns: []schema.Column{
+		{Name: "id", Type: "INTEGER"},
+		{Name: "post_id", Type: "INTEGER", Constraints: references("posts")},
+		{Name: "parent_id", Type: "INTEGER", Constraints: references("comments")},
+	}}
+	teams := schema.Table{Name: "teams", Columns: []schema.Column{{Name: "id", Type: "INTEGER"}, {Name: "owner_id", Type: "INTEGER", Constraints: references("members")}}}
+	members := schema.Table{
+		Name:    "members",
+		Columns: []schema.Column{{Name: "id", Type: "INTEGER"}, {Name: "team_id", Type: "INTEGER"}},
+		Constraints: []schema.TableConstraint{
+			{Name: "members_team_fkey", Type: schema.ForeignKeyConstraintType, Columns: []string{"team_id"}, ReferencedTable: "teams", ReferencedColumns: []string{"id"}},
+		},
+	}
+	tests := []struct {
+		name       string
+		desired    []schema.Table
+		live       []schema.Table
+		split      bool
+		want       []string
+		wantCycles []string
+	}{
+		{
+			name:    "create referenced tables first",
+			desired: []schema.Table{comments, posts, users},
+			split:   true,
+			want:    []string{"+ table users", "+ table posts", "+ table comments"},
+		},
+		{
+			name:  "drop referencing tables first",
+			live:  []schema.Table{users, posts, comments},
+			split: true,
+			want:  []string{"- table comments", "- table posts", "- table users"},
+		},
+		{
+			name:    "split cycle on create",
+			desired: []schema.Table{teams, members},
+			split:   true,
+			want: []string{
+				"+ table members",
+				"+ table teams",
+				"+ constraint members_team_fkey FOREIGN KEY (team_id) REFERENCES teams (id) on members",
+			},
+			wantCycles: []string{"teams -> members -> teams"},
+		},
+		{
+			name:  "split cycle on drop",
+			live:  []schema.Table{teams, members},
+			split: true,
+			want: []string{
+				"- constraint ForeignKey(members.id) on teams.owner_id",
+				"- table members",
+				"- table teams",
+			},
+			wantCycles: []string{"teams -> members -> teams"},
+		},
+		{
+			name:    "cycle left as is",
+			desired: []schema.Table{teams, members, users},
+			want:    []string{"+ table users", "+ table teams", "+ table members"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := orderTables(Compare(tt.desired, tt.live), tt.split)
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderTables() = %q, want %q", got, tt.want)
+			}
+			var cycles []string
+			for _, change := range changes.Cycles() {
+				cycles = append(cycles, cycleString(change.Cycle))
+			}
+			if !reflect.DeepEqual(cycles, tt.wantCycles) {
+				t.Errorf("Cycles() = %q, want %q", cycles, tt.wantCycles)
+			}
+		})
+	}
+}
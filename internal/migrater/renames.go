@@ -0,0 +1,191 @@
+package migrater
+
+import (
+	"slices"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// renames holds the new names of renamed tables by previous name, and for
+// each table, by its new name, the new names of its renamed columns.
+type renames struct {
+	tables  map[string]string
+	columns map[string]map[string]string
+}
+
+// findRenames returns the rename changes hinted by the desired tables. A
+// previous name is only followed when it exists in the live schema, the new
+// name does not, and no desired table or column still uses it.
+func findRenames(desired []schema.Table, live []schema.Table) (ChangeSet, renames) {
+	changes := make(ChangeSet, 0)
+	r := renames{tables: map[string]string{}, columns: map[string]map[string]string{}}
+	liveTables := make(map[string]schema.Table, len(live))
+	for _, table := range live {
+		liveTables[table.Name] = table
+	}
+	desiredTables := make(map[string]bool, len(desired))
+	for _, table := range desired {
+		desiredTables[table.Name] = true
+	}
+	for _, table := range desired {
+		liveTable, ok := liveTables[table.Name]
+		if !ok && table.RenamedFrom != "" && !desiredTables[table.RenamedFrom] {
+			liveTable, ok = liveTables[table.RenamedFrom]
+			if ok {
+				changes = append(changes, Change{Kind: RenameTableChange, Table: table, PreviousTable: liveTable})
+				r.tables[liveTable.Name] = table.Name
+			}
+		}
+		if !ok {
+			continue
+		}
+		liveColumns := make(map[string]schema.Column, len(liveTable.Columns))
+		for _, column := range liveTable.Columns {
+			liveColumns[column.Name] = column
+		}
+		desiredColumns := make(map[string]bool, len(table.Columns))
+		for _, column := range table.Columns {
+			desiredColumns[column.Name] = true
+		}
+		for _, column := range table.Columns {
+			if column.RenamedFrom == "" || desiredColumns[column.RenamedFrom] {
+				continue
+			}
+			if _, exists := liveColumns[column.Name]; exists {
+				continue
+			}
+			previous, found := liveColumns[column.RenamedFrom]
+			if !found {
+				continue
+			}
+			changes = append(changes, Change{Kind: RenameColumnChange, Table: table, Column: column, Previous: previous})
+			if r.columns[table.Name] == nil {
+				r.columns[table.Name] = map[string]string{}
+			}
+			r.columns[table.Name][previous.Name] = column.Name
+		}
+	}
+	return changes, r
+}
+
+func (r renames) table(name string) string {
+	if renamed, ok := r.tables[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// column returns the new name of a column of a table given by its new name.
+func (r renames) column(table string, name string) string {
+	if renamed, ok := r.columns[table][name]; ok {
+		return renamed
+	}
+	return name
+}
+
+func (r renames) columnNames(table string, names []string) []string {
+	if names == nil {
+		return nil
+	}
+	renamed := make([]string, 0, len(names))
+	for _, name := range names {
+		renamed = append(renamed, r.column(table, name))
+	}
+	return renamed
+}
+
+// apply returns the live tables as they are once renamed. Databases update
+// the keys, indexes and foreign keys that use a renamed table or column, so
+// they are renamed as well.
+func (r renames) apply(tables []schema.Table) []schema.Table {
+	if len(r.tables) == 0 && len(r.columns) == 0 {
+		return tables
+	}
+	renamed := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		table.Name = r.table(table.Name)
+		columns := make([]schema.Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			column.Name = r.column(table.Name, column.Name)
+			column.Constraints = slices.Clone(column.Constraints)
+			for i, constraint := range column.Constraints {
+				if fk, ok := constraint.(schema.ForeignKeyConstraint); ok {
+					fk.ReferencedTable = r.table(fk.ReferencedTable)
+					fk.ReferencedColumn = r.column(fk.ReferencedTable, fk.ReferencedColumn)
+					column.Constraints[i] = fk
+				}
+			}
+			columns = append(columns, column)
+		}
+		table.Columns = columns
+		table.Constraints = slices.Clone(table.Constraints)
+		for i, constraint := range table.Constraints {
+			constraint.Columns = r.columnNames(table.Name, constraint.Columns)
+			if constraint.ReferencedTable != "" {
+				constraint.ReferencedTable = r.table(constraint.ReferencedTable)
+				constraint.ReferencedColumns = r.columnNames(constraint.ReferencedTable, constraint.ReferencedColumns)
+			}
+			table.Constraints[i] = constraint
+		}
+		table.Indexes = slices.Clone(table.Indexes)
+		for i, index := range table.Indexes {
+			index.Columns = r.columnNames(table.Name, index.Columns)
+			table.Indexes[i] = index
+		}
+		renamed = append(renamed, table)
+	}
+	return renamed
+}
+
+// historyRenames returns the renames of a change set, as recorded in the
+// history.
+func historyRenames(changes ChangeSet) []HistoryRename {
+	renames := make([]HistoryRename, 0)
+	for _, change := range changes {
+		switch change.Kind {
+		case RenameTableChange:
+			renames = append(renames, HistoryRename{Kind: RenameTableChange, Table: change.Table.Name, Previous: change.PreviousTable.Name})
+		case RenameColumnChange:
+			renames = append(renames, HistoryRename{Kind: RenameColumnChange, Table: change.Table.Name, Column: change.Column.Name, Previous: change.Previous.Name})
+		}
+	}
+	return renames
+}
+
+// withoutAppliedRenames clears the previous names of tables and columns
+// whose rename is recorded in a successful migration, so that a hint left in
+// the schema is not followed again once the previous name is reused.
+func withoutAppliedRenames(tables []schema.Table, entries []HistoryEntry) []schema.Table {
+	applied := make(map[HistoryRename]bool)
+	for _, entry := range entries {
+		if entry.Status != HistorySuccess {
+			continue
+		}
+		for _, rename := range entry.Renames {
+			applied[rename] = true
+		}
+	}
+	if len(applied) == 0 {
+		return tables
+	}
+	cleared := make([]schema.Table, 0, len(tables))
+	for _, table := range tables {
+		if table.RenamedFrom != "" {
+			if applied[HistoryRename{Kind: RenameTableChange, Table: table.Name, Previous: table.RenamedFrom}] {
+				table.RenamedFrom = ""
+			}
+		}
+		columns := make([]schema.Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			if column.RenamedFrom != "" {
+				if applied[HistoryRename{Kind: RenameColumnChange, Table: table.Name, Column: column.Name, Previous: column.RenamedFrom}] {
+					column.RenamedFrom = ""
+				}
+			}
+			columns = append(columns, column)
+		}
+		table.Columns = columns
+		cleared = append(cleared, table)
+	}
+	return cleared
+}
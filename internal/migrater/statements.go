@@ -0,0 +1,193 @@
+package migrater
+
+import (
+	"fmt"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/migrater/internal/db/drivers"
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// Statement is a single SQL statement of a migration plan along with the
+// table and column it affects. ForeignKeyCheck marks a query listing the
+// rows of Table violating its foreign keys, the migration fails when it
+// returns any. NoTransaction marks a statement which has no effect within
+// a transaction: leading ones run before the migration transaction and
+// the others after it.
+type Statement struct {
+	Table           string
+	Column          string
+	SQL             string
+	ForeignKeyCheck bool
+	NoTransaction   bool
+}
+
+// Target returns the table, or table.column, affected by the statement.
+func (s Statement) Target() string {
+	if s.Column == "" {
+		return s.Table
+	}
+	return fmt.Sprintf("%s.%s", s.Table, s.Column)
+}
+
+func (s Statement) String() string {
+	return s.SQL + ";"
+}
+
+// changeStatements renders a change with the DDL of the given dialect.
+func changeStatements(dialect drivers.Dialect, change Change) ([]Statement, errors.Error) {
+	var sqls []string
+	var err errors.Error
+	column := change.Column.Name
+	switch change.Kind {
+	case AddTableChange:
+		sqls, err = dialect.CreateTable(change.Table)
+		column = ""
+	case DropTableChange:
+		sqls, err = dialect.DropTable(change.Table)
+		column = ""
+	case RenameTableChange:
+		sqls, err = dialect.RenameTable(change.PreviousTable.Name, change.Table)
+		column = ""
+	case RenameColumnChange:
+		sqls, err = dialect.RenameColumn(change.Table, change.Previous.Name, change.Column)
+	case AddColumnChange:
+		sqls, err = dialect.AddColumn(change.Table, change.Column)
+	case DropColumnChange:
+		sqls, err = dialect.DropColumn(change.Table, change.Column)
+	case AlterColumnTypeChange:
+		sqls, err = dialect.AlterColumnType(change.Table, change.Column)
+	case AddConstraintChange:
+		sqls, err = dialect.AddConstraint(change.Table, change.Column, change.Constraint)
+	case DropConstraintChange:
+		sqls, err = dialect.DropConstraint(change.Table, change.Column, change.Constraint)
+	case AddTableConstraintChange:
+		sqls, err = dialect.AddTableConstraint(change.Table, change.TableConstraint)
+		column = ""
+	case DropTableConstraintChange:
+		sqls, err = dialect.DropTableConstraint(change.Table, change.TableConstraint)
+		column = ""
+	case AddIndexChange:
+		sqls, err = dialect.CreateIndex(change.Table, change.Index)
+		column = ""
+	case DropIndexChange:
+		sqls, err = dialect.DropIndex(change.Table, change.Index)
+		column = ""
+	default:
+		err = errors.New(fmt.Sprintf("unsupported change: %s", change.Kind))
+	}
+	if err != nil {
+		return nil, err
+	}
+	statements := make([]Statement, 0, len(sqls))
+	for _, sql := range sqls {
+		statements = append(statements, Statement{Table: change.Table.Name, Column: column, SQL: sql})
+	}
+	return statements, nil
+}
+
+// rebuildableChange reports whether a change can be applied by rebuilding
+// its table. Tables are added, dropped and renamed as usual, and columns
+// renamed before the table is rebuilt.
+func rebuildableChange(change Change) bool {
+	switch change.Kind {
+	case AddTableChange, DropTableChange, RenameTableChange, RenameColumnChange:
+		return false
+	default:
+		return true
+	}
+}
+
+// rebuiltTables returns the tables with a change the dialect cannot render,
+// which are rebuilt instead of altered.
+func rebuiltTables(dialect drivers.Dialect, changes ChangeSet) map[string]bool {
+	rebuilt := make(map[string]bool)
+	for _, change := range changes {
+		if !rebuildableChange(change) || rebuilt[change.Table.Name] {
+			continue
+		}
+		if _, err := changeStatements(dialect, change); err != nil {
+			rebuilt[change.Table.Name] = true
+		}
+	}
+	return rebuilt
+}
+
+// rebuildStatements rebuilds a table into its desired definition. The
+// columns added by the changes start with their defaults, the others are
+// copied, renamed columns having already been renamed in the live table.
+// The indexes dropped by the changes are not created again.
+func rebuildStatements(rebuilder drivers.TableRebuilder, table schema.Table, changes ChangeSet) ([]Statement, errors.Error) {
+	previousName := table.Name
+	added := make(map[string]bool)
+	var droppedIndexes []string
+	for _, change := range changes {
+		if change.Table.Name != table.Name {
+			continue
+		}
+		switch change.Kind {
+		case RenameTableChange:
+			previousName = change.PreviousTable.Name
+		case AddColumnChange:
+			added[change.Column.Name] = true
+		case DropIndexChange:
+			droppedIndexes = append(droppedIndexes, change.Index.Name)
+		}
+	}
+	columns := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		if !added[column.Name] {
+			columns = append(columns, column.Name)
+		}
+	}
+	sqls, err := rebuilder.RebuildTable(previousName, table, columns, droppedIndexes)
+	if err != nil {
+		return nil, err
+	}
+	statements := make([]Statement, 0, len(sqls))
+	for _, sql := range sqls {
+		statements = append(statements, Statement{Table: table.Name, SQL: sql})
+	}
+	return statements, nil
+}
+
+// planStatements renders the changes in order. With a dialect rebuilding
+// tables, the changes of a table it cannot alter are replaced by a rebuild
+// of the table in place of the first one. Foreign keys are then disabled
+// for the migration and the rebuilt tables checked at its end.
+func planStatements(dialect drivers.Dialect, changes ChangeSet) ([]Statement, errors.Error) {
+	rebuilder, ok := dialect.(drivers.TableRebuilder)
+	rebuilt := map[string]bool{}
+	if ok {
+		rebuilt = rebuiltTables(dialect, changes)
+	}
+	statements := make([]Statement, 0, len(changes))
+	checks := make([]Statement, 0, len(rebuilt))
+	done := make(map[string]bool, len(rebuilt))
+	for _, change := range changes {
+		if !rebuilt[change.Table.Name] || !rebuildableChange(change) {
+			changeStatements, err := changeStatements(dialect, change)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, changeStatements...)
+			continue
+		}
+		if done[change.Table.Name] {
+			continue
+		}
+		done[change.Table.Name] = true
+		rebuild, err := rebuildStatements(rebuilder, change.Table, changes)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, rebuild...)
+		checks = append(checks, Statement{Table: change.Table.Name, SQL: rebuilder.ForeignKeyCheck(change.Table), ForeignKeyCheck: true})
+	}
+	if len(checks) == 0 {
+		return statements, nil
+	}
+	statements = append([]Statement{{SQL: rebuilder.ForeignKeys(false), NoTransaction: true}}, statements...)
+	statements = append(statements, checks...)
+	return append(statements, Statement{SQL: rebuilder.ForeignKeys(true), NoTransaction: true}), nil
+}
@@ -0,0 +1,84 @@
+package migrater
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/yassirdeveloper/migrater/internal/schema"
+)
+
+// integerTypes are integer types from the smallest to the largest, types of
+// the same size share a rank.
+var integerTypes = map[string]int{
+	"TINYINT":   1,
+	"SMALLINT":  2,
+	"INT2":      2,
+	"MEDIUMINT": 3,
+	"INT":       4,
+	"INTEGER":   4,
+	"INT4":      4,
+	"BIGINT":    5,
+	"INT8":      5,
+}
+
+var characterTypes = []string{"CHAR", "CHARACTER", "VARCHAR", "CHARACTER VARYING", "NCHAR", "NVARCHAR"}
+
+// numericArguments returns the arguments of a type as numbers, ok is false
+// when one of them is not a number.
+func numericArguments(parsed schema.ParsedType) (arguments []int, ok bool) {
+	for _, argument := range parsed.Arguments {
+		value, err := strconv.Atoi(argument)
+		if err != nil {
+			return nil, false
+		}
+		arguments = append(arguments, value)
+	}
+	return arguments, true
+}
+
+// widensType reports whether every value of the previous type fits in the
+// next one: a larger length, precision or scale of the same type, a larger integer
+// type, or character types becoming TEXT. Other type changes are assumed to
+// lose data. A type written without arguments has an unknown width, e.g.
+// CHAR is CHAR(1) and DECIMAL has a scale of 0 on most databases while
+// VARCHAR is unbounded on postgres, and a type gaining or losing its
+// arguments is assumed to be narrower. Types are normalized before they
+// are compared, which gives them the default width of their dialect.
+func widensType(previous schema.DataType, next schema.DataType) bool {
+	previousType := previous.Parse()
+	nextType := next.Parse()
+	if previousType.Unsigned != nextType.Unsigned || previousType.ArrayDimensions != nextType.ArrayDimensions {
+		return false
+	}
+	previousName, nextName := previousType.Base, nextType.Base
+	previousArguments, ok := numericArguments(previousType)
+	if !ok {
+		return false
+	}
+	nextArguments, ok := numericArguments(nextType)
+	if !ok {
+		return false
+	}
+	if previousName == nextName {
+		if len(previousArguments) != len(nextArguments) {
+			return false
+		}
+		for i := range nextArguments {
+			if nextArguments[i] < previousArguments[i] {
+				return false
+			}
+		}
+		// The digits before the decimal point of a precision and scale
+		// must not shrink either.
+		if len(nextArguments) == 2 {
+			return nextArguments[0]-nextArguments[1] >= previousArguments[0]-previousArguments[1]
+		}
+		return true
+	}
+	previousRank, previousInteger := integerTypes[previousName]
+	nextRank, nextInteger := integerTypes[nextName]
+	if previousInteger && nextInteger {
+		return nextRank >= previousRank
+	}
+	return nextName == "TEXT" && slices.Contains(characterTypes, previousName)
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Constraint types used in the json representation. Constraints without
+// parameters can be written as a plain string, e.g. "not_null", the others
+// as an object with a "type" field, e.g. {"type": "default", "value": "0"}.
+const (
+	NotNullConstraintType       = "not_null"
+	PrimaryKeyConstraintType    = "primary_key"
+	UniqueConstraintType        = "unique"
+	AutoIncrementConstraintType = "auto_increment"
+	CheckConstraintType         = "check"
+	DefaultConstraintType       = "default"
+	ForeignKeyConstraintType    = "foreign_key"
+)
+
+type Constraints []Constraint
+
+func (c Constraints) String() string {
+	names := make([]string, 0, len(c))
+	for _, constraint := range c {
+		names = append(names, constraint.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (c *Constraints) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	constraints := make(Constraints, 0, len(raw))
+	for _, r := range raw {
+		constraint, err := UnmarshalConstraint(r)
+		if err != nil {
+			return err
+		}
+		constraints = append(constraints, constraint)
+	}
+	*c = constraints
+	return nil
+}
+
+// UnmarshalConstraint decodes a single constraint from its shorthand string
+// or tagged object representation.
+func UnmarshalConstraint(data []byte) (Constraint, error) {
+	var constraintType string
+	if err := json.Unmarshal(data, &constraintType); err != nil {
+		var tagged struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(data, &tagged); err != nil {
+			return nil, fmt.Errorf("invalid constraint: %s", data)
+		}
+		constraintType = tagged.Type
+	} else {
+		data = nil
+	}
+	var constraint Constraint
+	switch constraintType {
+	case NotNullConstraintType:
+		constraint = NotNullConstraint{}
+	case PrimaryKeyConstraintType:
+		constraint = PrimaryKeyConstraint{}
+	case UniqueConstraintType:
+		constraint = UniqueConstraint{}
+	case AutoIncrementConstraintType:
+		constraint = AutoIncrementConstraint{}
+	case CheckConstraintType:
+		var c CheckConstraint
+		if err := unmarshalFields(data, &c); err != nil {
+			return nil, err
+		}
+		if c.Expression == "" {
+			return nil, fmt.Errorf("check constraint requires an expression")
+		}
+		constraint = c
+	case DefaultConstraintType:
+		var c DefaultConstraint
+		if err := unmarshalFields(data, &c); err != nil {
+			return nil, err
+		}
+		if c.Value == "" {
+			return nil, fmt.Errorf("default constraint requires a value")
+		}
+		constraint = c
+	case ForeignKeyConstraintType:
+		var c ForeignKeyConstraint
+		if err := unmarshalFields(data, &c); err != nil {
+			return nil, err
+		}
+		if c.ReferencedTable == "" || c.ReferencedColumn == "" {
+			return nil, fmt.Errorf("foreign key constraint requires a referenced_table and a referenced_column")
+		}
+		constraint = c
+	case "":
+		return nil, fmt.Errorf("missing constraint type: %s", data)
+	default:
+		return nil, fmt.Errorf("unknown constraint type: %s", constraintType)
+	}
+	return constraint, nil
+}
+
+// unmarshalFields decodes the fields of a tagged constraint object, data is
+// nil when the constraint was written as a shorthand string.
+func unmarshalFields(data []byte, v any) error {
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// marshalTagged encodes the fields of a constraint along with its type.
+func marshalTagged(constraintType string, fields any) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	tagged := fmt.Sprintf("{\"type\":%q", constraintType)
+	if string(data) != "{}" {
+		tagged += "," + string(data[1:])
+	} else {
+		tagged += "}"
+	}
+	return []byte(tagged), nil
+}
+
+func (c NotNullConstraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NotNullConstraintType)
+}
+
+func (c PrimaryKeyConstraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(PrimaryKeyConstraintType)
+}
+
+func (c UniqueConstraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(UniqueConstraintType)
+}
+
+func (c AutoIncrementConstraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(AutoIncrementConstraintType)
+}
+
+func (c CheckConstraint) MarshalJSON() ([]byte, error) {
+	type fields CheckConstraint
+	return marshalTagged(CheckConstraintType, fields(c))
+}
+
+func (c DefaultConstraint) MarshalJSON() ([]byte, error) {
+	type fields DefaultConstraint
+	return marshalTagged(DefaultConstraintType, fields(c))
+}
+
+func (c ForeignKeyConstraint) MarshalJSON() ([]byte, error) {
+	type fields ForeignKeyConstraint
+	return marshalTagged(ForeignKeyConstraintType, fields(c))
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestColumnJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Column
+		wantErr bool
+	}{
+		{
+			name: "shorthand constraints",
+			data: `{"name": "id", "type": "INTEGER", "constraints": ["not_null", "primary_key", "unique", "auto_increment"]}`,
+			want: Column{
+				Name:        "id",
+				Type:        "INTEGER",
+				Constraints: Constraints{NotNullConstraint{}, PrimaryKeyConstraint{}, UniqueConstraint{}, AutoIncrementConstraint{}},
+			},
+		},
+		{
+			name: "tagged constraints",
+			data: `{"name": "user_id", "type": "INTEGER", "constraints": [
+				{"type": "not_null"},
+				{"type": "default", "value": "0"},
+				{"type": "check", "expression": "user_id >= 0"},
+				{"type": "foreign_key", "referenced_table": "users", "referenced_column": "id", "on_delete": "CASCADE"}
+			]}`,
+			want: Column{
+				Name: "user_id",
+				Type: "INTEGER",
+				Constraints: Constraints{
+					NotNullConstraint{},
+					DefaultConstraint{Value: "0"},
+					CheckConstraint{Expression: "user_id >= 0"},
+					ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"},
+				},
+			},
+		},
+		{
+			name:    "unknown constraint",
+			data:    `{"name": "id", "type": "INTEGER", "constraints": ["primary"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "foreign key without reference",
+			data:    `{"name": "id", "type": "INTEGER", "constraints": ["foreign_key"]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Column
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var roundTrip Column
+			if err := json.Unmarshal(data, &roundTrip); err != nil {
+				t.Fatalf("Unmarshal() of %s error = %v", data, err)
+			}
+			if !reflect.DeepEqual(roundTrip, tt.want) {
+				t.Errorf("round trip = %v, want %v", roundTrip, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Constraints{
+		NotNullConstraint{},
+		ForeignKeyConstraint{ReferencedTable: "users", ReferencedColumn: "id"},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `["not_null",{"type":"foreign_key","referenced_table":"users","referenced_column":"id"}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -15,16 +16,123 @@ func (t DataType) String() string {
 	return string(t)
 }
 
+// Table is a table of a database. RenamedFrom is the previous name of the
+// table, so that it is renamed instead of being dropped and created again.
 type Table struct {
+	Name        string            `json:"name"`
+	RenamedFrom string            `json:"renamed_from,omitempty"`
+	Columns     []Column          `json:"columns"`
+	Constraints []TableConstraint `json:"constraints,omitempty"`
+	Indexes     []Index           `json:"indexes,omitempty"`
+}
+
+// TableConstraint is a named constraint declared on the table, which can
+// span several columns. Type is one of PrimaryKeyConstraintType,
+// UniqueConstraintType, ForeignKeyConstraintType or CheckConstraintType.
+type TableConstraint struct {
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Columns           []string `json:"columns,omitempty"`
+	ReferencedTable   string   `json:"referenced_table,omitempty"`
+	ReferencedColumns []string `json:"referenced_columns,omitempty"`
+	OnDelete          string   `json:"on_delete,omitempty"`
+	OnUpdate          string   `json:"on_update,omitempty"`
+	Expression        string   `json:"expression,omitempty"`
+}
+
+// Equals reports whether two table constraints have the same definition.
+func (c TableConstraint) Equals(other TableConstraint) bool {
+	return c.Name == other.Name &&
+		c.Type == other.Type &&
+		slices.Equal(c.Columns, other.Columns) &&
+		c.ReferencedTable == other.ReferencedTable &&
+		slices.Equal(c.ReferencedColumns, other.ReferencedColumns) &&
+		strings.EqualFold(c.OnDelete, other.OnDelete) &&
+		strings.EqualFold(c.OnUpdate, other.OnUpdate) &&
+		c.Expression == other.Expression
+}
+
+func (c TableConstraint) String() string {
+	columns := strings.Join(c.Columns, ", ")
+	switch c.Type {
+	case PrimaryKeyConstraintType:
+		return fmt.Sprintf("%s PRIMARY KEY (%s)", c.Name, columns)
+	case UniqueConstraintType:
+		return fmt.Sprintf("%s UNIQUE (%s)", c.Name, columns)
+	case ForeignKeyConstraintType:
+		definition := fmt.Sprintf("%s FOREIGN KEY (%s) REFERENCES %s (%s)", c.Name, columns, c.ReferencedTable, strings.Join(c.ReferencedColumns, ", "))
+		return definition + referentialActions(c.OnDelete, c.OnUpdate)
+	case CheckConstraintType:
+		return fmt.Sprintf("%s CHECK (%s)", c.Name, c.Expression)
+	default:
+		return fmt.Sprintf("%s %s", c.Name, c.Type)
+	}
+}
+
+// Index is a secondary index of a table. Method is the index method, e.g.
+// btree, hash or gin, and defaults to the database's own default. Where
+// makes it a partial index.
+type Index struct {
 	Name    string   `json:"name"`
-	Columns []Column `json:"columns"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	Method  string   `json:"method,omitempty"`
+	Where   string   `json:"where,omitempty"`
+}
+
+// Equals reports whether two indexes have the same definition, btree being
+// the default method.
+func (i Index) Equals(other Index) bool {
+	return i.Name == other.Name &&
+		slices.Equal(i.Columns, other.Columns) &&
+		i.Unique == other.Unique &&
+		strings.EqualFold(i.method(), other.method()) &&
+		i.Where == other.Where
+}
+
+func (i Index) method() string {
+	if i.Method == "" {
+		return "btree"
+	}
+	return i.Method
 }
 
+func (i Index) String() string {
+	definition := fmt.Sprintf("%s (%s)", i.Name, strings.Join(i.Columns, ", "))
+	if i.Unique {
+		definition += " UNIQUE"
+	}
+	if i.Method != "" {
+		definition += " USING " + i.Method
+	}
+	if i.Where != "" {
+		definition += " WHERE " + i.Where
+	}
+	return definition
+}
+
+// Column is a column of a table. RenamedFrom is the previous name of the
+// column, so that it is renamed instead of being dropped and added again.
 type Column struct {
-	Name        string       `json:"name"`
-	Type        DataType     `json:"type"`
-	Default     string       `json:"default,omitempty"`
-	Constraints []Constraint `json:"constraints"`
+	Name        string      `json:"name"`
+	RenamedFrom string      `json:"renamed_from,omitempty"`
+	Type        DataType    `json:"type"`
+	Default     string      `json:"default,omitempty"`
+	Constraints Constraints `json:"constraints,omitempty"`
+}
+
+// GetConstraints returns the constraints of the column, including the
+// Default field as a DefaultConstraint.
+func (c Column) GetConstraints() []Constraint {
+	constraints := make([]Constraint, 0, len(c.Constraints)+1)
+	constraints = append(constraints, c.Constraints...)
+	if c.Default != "" {
+		constraint := DefaultConstraint{Value: c.Default}
+		if !HasConstraint(constraints, constraint) {
+			constraints = append(constraints, constraint)
+		}
+	}
+	return constraints
 }
 
 type Constraint interface {
@@ -49,10 +157,21 @@ func (c UniqueConstraint) Name() string {
 	return "Unique"
 }
 
-type CheckConstraint struct{}
+// AutoIncrementConstraint marks a column whose value is generated from a
+// sequence when it is not given, e.g. AUTO_INCREMENT in mysql or an
+// identity column in postgres.
+type AutoIncrementConstraint struct{}
+
+func (c AutoIncrementConstraint) Name() string {
+	return "AutoIncrement"
+}
+
+type CheckConstraint struct {
+	Expression string `json:"expression"`
+}
 
 func (c CheckConstraint) Name() string {
-	return "Check"
+	return fmt.Sprintf("Check(%s)", c.Expression)
 }
 
 type DefaultConstraint struct {
@@ -70,6 +189,28 @@ type ForeignKeyConstraint struct {
 	OnUpdate         string `json:"on_update,omitempty"`
 }
 
+// Name includes the referential actions, so that a foreign key whose
+// actions changed is dropped and added again.
 func (c ForeignKeyConstraint) Name() string {
-	return fmt.Sprintf("ForeignKey(%s.%s)", c.ReferencedTable, c.ReferencedColumn)
+	return fmt.Sprintf("ForeignKey(%s.%s%s)", c.ReferencedTable, c.ReferencedColumn, strings.ToUpper(referentialActions(c.OnDelete, c.OnUpdate)))
+}
+
+func referentialActions(onDelete string, onUpdate string) string {
+	actions := ""
+	if onDelete != "" {
+		actions += " ON DELETE " + onDelete
+	}
+	if onUpdate != "" {
+		actions += " ON UPDATE " + onUpdate
+	}
+	return actions
+}
+
+func HasConstraint(constraints []Constraint, constraint Constraint) bool {
+	for _, c := range constraints {
+		if c.Name() == constraint.Name() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LogicalType is a portable type that each driver maps to one of its own
+// types, so that the same schema can target several databases. Logical
+// types are written in lower case, e.g. string(255) or decimal(10,2), other
+// types are passed to the driver as they are.
+type LogicalType string
+
+const (
+	Int32Type     LogicalType = "int32"
+	Int64Type     LogicalType = "int64"
+	StringType    LogicalType = "string"
+	DecimalType   LogicalType = "decimal"
+	BoolType      LogicalType = "bool"
+	TimestampType LogicalType = "timestamp"
+	UUIDType      LogicalType = "uuid"
+	JSONType      LogicalType = "json"
+	BytesType     LogicalType = "bytes"
+)
+
+var LogicalTypes = []LogicalType{
+	Int32Type,
+	Int64Type,
+	StringType,
+	DecimalType,
+	BoolType,
+	TimestampType,
+	UUIDType,
+	JSONType,
+	BytesType,
+}
+
+var logicalTypePattern = regexp.MustCompile(`^([a-z0-9]+)(?:\(\s*([0-9]+(?:\s*,\s*[0-9]+)*)\s*\))?$`)
+
+// MaxArguments returns the number of arguments the type accepts: the length
+// of a string, the precision and scale of a decimal.
+func (t LogicalType) MaxArguments() int {
+	switch t {
+	case StringType:
+		return 1
+	case DecimalType:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Logical returns the logical type the type is written as and its
+// arguments. ok is false for driver types.
+func (t DataType) Logical() (logical LogicalType, arguments []string, ok bool) {
+	match := logicalTypePattern.FindStringSubmatch(string(t))
+	if match == nil || !slices.Contains(LogicalTypes, LogicalType(match[1])) {
+		return "", nil, false
+	}
+	if match[2] != "" {
+		for _, argument := range strings.Split(match[2], ",") {
+			arguments = append(arguments, strings.TrimSpace(argument))
+		}
+	}
+	return LogicalType(match[1]), arguments, true
+}
+
+// ParsedType is a type split into its parts, e.g. NUMERIC(10,2),
+// INT UNSIGNED or TEXT[]. Base is in upper case with single spaces.
+// ArgumentsPosition is the number of words of Base written before the
+// arguments, as in TIMESTAMP(6) WITH TIME ZONE, 0 when they follow Base.
+type ParsedType struct {
+	Base              string
+	Arguments         []string
+	ArgumentsPosition int
+	Unsigned          bool
+	ArrayDimensions   int
+}
+
+var (
+	arraySuffix  = regexp.MustCompile(`\s*\[\s*[0-9]*\s*\]\s*$`)
+	unsignedWord = regexp.MustCompile(`(?i)\s+UNSIGNED\b`)
+)
+
+// Parse splits the type into its base name, arguments, unsigned flag and
+// array dimensions.
+func (t DataType) Parse() ParsedType {
+	var parsed ParsedType
+	rest := strings.TrimSpace(string(t))
+	for arraySuffix.MatchString(rest) {
+		rest = arraySuffix.ReplaceAllString(rest, "")
+		parsed.ArrayDimensions++
+	}
+	if unsignedWord.MatchString(rest) {
+		parsed.Unsigned = true
+		rest = unsignedWord.ReplaceAllString(rest, "")
+	}
+	if start, end, ok := argumentsSpan(rest); ok {
+		parsed.Arguments = splitArguments(rest[start+1 : end])
+		if strings.TrimSpace(rest[end+1:]) != "" {
+			parsed.ArgumentsPosition = len(strings.Fields(rest[:start]))
+		}
+		rest = rest[:start] + " " + rest[end+1:]
+	}
+	parsed.Base = strings.ToUpper(strings.Join(strings.Fields(rest), " "))
+	return parsed
+}
+
+// argumentsSpan returns the positions of the first opening parenthesis of a
+// type and of its matching closing parenthesis, arguments being types
+// themselves in nested types such as STRUCT(a DECIMAL(10,2)).
+func argumentsSpan(t string) (int, int, bool) {
+	start := strings.Index(t, "(")
+	if start == -1 {
+		return 0, 0, false
+	}
+	depth := 0
+	quoted := false
+	for i := start; i < len(t); i++ {
+		switch {
+		case t[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case t[i] == '(':
+			depth++
+		case t[i] == ')':
+			depth--
+			if depth == 0 {
+				return start, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// splitArguments splits arguments on the commas that are neither quoted nor
+// nested, as in ENUM('a,b', 'c') or MAP(VARCHAR, DECIMAL(10,2)).
+func splitArguments(arguments string) []string {
+	if strings.TrimSpace(arguments) == "" {
+		return nil
+	}
+	split := make([]string, 0)
+	quoted := false
+	depth := 0
+	start := 0
+	for i, r := range arguments {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			split = append(split, strings.TrimSpace(arguments[start:i]))
+			start = i + 1
+		}
+	}
+	return append(split, strings.TrimSpace(arguments[start:]))
+}
+
+func (t ParsedType) String() string {
+	definition := t.Base
+	if len(t.Arguments) > 0 {
+		arguments := "(" + strings.Join(t.Arguments, ",") + ")"
+		words := strings.Fields(t.Base)
+		if t.ArgumentsPosition > 0 && t.ArgumentsPosition < len(words) {
+			definition = strings.Join(words[:t.ArgumentsPosition], " ") + arguments + " " + strings.Join(words[t.ArgumentsPosition:], " ")
+		} else {
+			definition += arguments
+		}
+	}
+	if t.Unsigned {
+		definition += " UNSIGNED"
+	}
+	return definition + strings.Repeat("[]", t.ArrayDimensions)
+}
+
+// DataType returns the type written in its canonical form.
+func (t ParsedType) DataType() DataType {
+	return DataType(t.String())
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLogical(t *testing.T) {
+	tests := []struct {
+		dataType  DataType
+		logical   LogicalType
+		arguments []string
+		ok        bool
+	}{
+		{dataType: "int64", logical: Int64Type, ok: true},
+		{dataType: "string(255)", logical: StringType, arguments: []string{"255"}, ok: true},
+		{dataType: "decimal( 10, 2 )", logical: DecimalType, arguments: []string{"10", "2"}, ok: true},
+		{dataType: "string(1,2)", logical: StringType, arguments: []string{"1", "2"}, ok: true},
+		{dataType: "VARCHAR(255)"},
+		{dataType: "JSON"},
+		{dataType: "string(n)"},
+		{dataType: "text"},
+	}
+	for _, tt := range tests {
+		logical, arguments, ok := tt.dataType.Logical()
+		if logical != tt.logical || !reflect.DeepEqual(arguments, tt.arguments) || ok != tt.ok {
+			t.Errorf("%s.Logical() = %q, %q, %v, want %q, %q, %v", tt.dataType, logical, arguments, ok, tt.logical, tt.arguments, tt.ok)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		dataType DataType
+		want     ParsedType
+		str      string
+	}{
+		{dataType: "varchar(255)", want: ParsedType{Base: "VARCHAR", Arguments: []string{"255"}}, str: "VARCHAR(255)"},
+		{dataType: "DECIMAL(10, 2) unsigned", want: ParsedType{Base: "DECIMAL", Arguments: []string{"10", "2"}, Unsigned: true}, str: "DECIMAL(10,2) UNSIGNED"},
+		{dataType: "timestamp(6) with  time zone", want: ParsedType{Base: "TIMESTAMP WITH TIME ZONE", Arguments: []string{"6"}, ArgumentsPosition: 1}, str: "TIMESTAMP(6) WITH TIME ZONE"},
+		{dataType: "TIME WITH TIME ZONE", want: ParsedType{Base: "TIME WITH TIME ZONE"}, str: "TIME WITH TIME ZONE"},
+		{dataType: "double precision(53)", want: ParsedType{Base: "DOUBLE PRECISION", Arguments: []string{"53"}}, str: "DOUBLE PRECISION(53)"},
+		{dataType: "int4[][]", want: ParsedType{Base: "INT4", ArrayDimensions: 2}, str: "INT4[][]"},
+		{dataType: "ENUM('a,b', 'c')", want: ParsedType{Base: "ENUM", Arguments: []string{"'a,b'", "'c'"}}, str: "ENUM('a,b','c')"},
+		{dataType: "character varying", want: ParsedType{Base: "CHARACTER VARYING"}, str: "CHARACTER VARYING"},
+		{dataType: "STRUCT(a DECIMAL(10, 2), b VARCHAR)[]", want: ParsedType{Base: "STRUCT", Arguments: []string{"a DECIMAL(10, 2)", "b VARCHAR"}, ArrayDimensions: 1}, str: "STRUCT(a DECIMAL(10, 2),b VARCHAR)[]"},
+		{dataType: "MAP(VARCHAR, INTEGER)", want: ParsedType{Base: "MAP", Arguments: []string{"VARCHAR", "INTEGER"}}, str: "MAP(VARCHAR,INTEGER)"},
+	}
+	for _, tt := range tests {
+		got := tt.dataType.Parse()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s.Parse() = %+v, want %+v", tt.dataType, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("%s.Parse().String() = %q, want %q", tt.dataType, got.String(), tt.str)
+		}
+	}
+}
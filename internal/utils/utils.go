@@ -15,6 +15,7 @@ const (
 	DSNFormatPostgres  DSNFormat = "postgres"
 	DSNFormatSQLServer DSNFormat = "sqlserver"
 	DSNFormatSQLite    DSNFormat = "sqlite"
+	DSNFormatDuckDB    DSNFormat = "duckdb"
 )
 
 type DSN struct {
@@ -37,6 +38,8 @@ func (c *DSN) String() string {
 		return fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s", c.User, c.Password, c.Host, c.Port, c.Database)
 	case DSNFormatSQLite:
 		return fmt.Sprintf("file:%s?cache=shared&mode=rwc", c.Database)
+	case DSNFormatDuckDB:
+		return c.Database
 	default:
 		return ""
 	}
@@ -158,12 +161,12 @@ func ToDSN(dsn string, format DSNFormat) (*DSN, errors.Error) {
 		conf.Database = strings.TrimPrefix(after[questionIdx+1:], "database=")
 		conf.format = DSNFormatSQLServer
 
-	case DSNFormatSQLite:
+	case DSNFormatSQLite, DSNFormatDuckDB:
 		// file:dbname?cache=shared&mode=rwc
 		if !strings.HasPrefix(dsn, "file:") {
 			return nil, errors.New("Could not parse DSN: missing 'file:' prefix")
 		}
-		conf.format = DSNFormatSQLite
+		conf.format = format
 		dbName := strings.TrimPrefix(dsn, "file:")
 		if strings.Contains(dbName, "?") {
 			parts := strings.SplitN(dbName, "?", 2)
@@ -81,6 +81,16 @@ func TestDSN(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "duckdb file dsn",
+			dsn:    "file:analytics.duckdb",
+			format: DSNFormatDuckDB,
+			want: &DSN{
+				Database: "analytics.duckdb",
+				format:   DSNFormatDuckDB,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	cli "github.com/yassirdeveloper/cli"
 	"github.com/yassirdeveloper/migrater/cmd"
@@ -11,11 +12,20 @@ const CLI_NAME = "migrater"
 const CLI_VERSION = "0.0.1"
 
 func main() {
+	// code is the exit code of the command run, set by the commands
+	// wrapped with WithExitCode when they fail.
+	code := 0
 	cli, err := cli.NewCli(CLI_NAME, CLI_VERSION)
 	if err != nil {
 		log.Fatal(err)
 	}
-	cli.AddCommand(cmd.ValidateCommand())
+	cli.AddCommand(cmd.InitCommand())
+	cli.AddCommand(cmd.WithExitCode(cmd.ValidateCommand(), &code))
 	cli.AddCommand(cmd.DescribeCommand())
+	cli.AddCommand(cmd.PullCommand())
+	cli.AddCommand(cmd.WithExitCode(cmd.MigrateCommand(CLI_VERSION), &code))
+	cli.AddCommand(cmd.WithExitCode(cmd.PlanCommand(CLI_VERSION), &code))
+	cli.AddCommand(cmd.WithExitCode(cmd.StatusCommand(CLI_VERSION), &code))
 	cli.Run(true)
+	os.Exit(code)
 }